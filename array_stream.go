@@ -0,0 +1,72 @@
+package ejson
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// DecodeArrayStream reads a JSON array and decodes its elements one at a
+// time, so that arrays too large to be loaded in memory can be processed.
+//
+// Each element is decoded and validated as done by Unmarshal, then passed to
+// fn along with validation errors if there are any. The pointers of these
+// errors start with the index of the element in the array. Processing stops
+// as soon as fn returns an error, which is then returned.
+//
+// Syntax and I/O errors cannot be recovered from and are returned directly.
+// If the top-level value is not an array, a validation error is returned.
+func DecodeArrayStream[T any](r io.Reader, fn func(index int, item T, errs ValidationErrors) error) error {
+	d := json.NewDecoder(r)
+
+	token, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return ValidationErrors{
+			&ValidationError{
+				Code:    "invalid_value_type",
+				Message: "value must be an array",
+			},
+		}
+	}
+
+	// If T is a pointer type, elements are decoded in a new value of the
+	// pointed type: decoding into a **T would skip validation since a pointer
+	// to a pointer is never Validatable.
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+
+	for index := 0; d.More(); index++ {
+		var item T
+		var errs ValidationErrors
+
+		var dest interface{} = &item
+		if itemType.Kind() == reflect.Pointer {
+			ptr := reflect.New(itemType.Elem())
+			reflect.ValueOf(&item).Elem().Set(ptr)
+			dest = ptr.Interface()
+		}
+
+		if err := UnmarshalDecoder(d, dest); err != nil {
+			validationErrs, ok := err.(ValidationErrors)
+			if !ok {
+				return err
+			}
+
+			errs = validationErrs
+			errs.prepend(NewPointer(index))
+		}
+
+		if err := fn(index, item, errs); err != nil {
+			return err
+		}
+	}
+
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package ejson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeArrayStream(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `[
+  {"String": "abc"},
+  {"String": "a", "Bars": [{"Integers": [1, 20]}]},
+  {"String": 42},
+  {"String": "def"}
+]`
+
+	var values []string
+	var pointers, codes []string
+
+	err := DecodeArrayStream(strings.NewReader(data),
+		func(i int, foo TestFoo, errs ValidationErrors) error {
+			values = append(values, foo.String)
+
+			for _, err := range errs {
+				pointers = append(pointers, err.Pointer.String())
+				codes = append(codes, err.Code)
+			}
+
+			return nil
+		})
+	if assert.NoError(err) {
+		assert.Equal([]string{"abc", "a", "", "def"}, values)
+		assert.Equal([]string{"/1/String", "/1/Bars/0/Integers/1",
+			"/2/String"}, pointers)
+		assert.Equal([]string{"string_too_short", "integer_too_large",
+			"invalid_value_type"}, codes)
+	}
+
+	// Pointer elements are validated as well
+	values = nil
+	pointers = nil
+
+	err = DecodeArrayStream(strings.NewReader(data),
+		func(i int, foo *TestFoo, errs ValidationErrors) error {
+			values = append(values, foo.String)
+
+			for _, err := range errs {
+				pointers = append(pointers, err.Pointer.String())
+			}
+
+			return nil
+		})
+	if assert.NoError(err) {
+		assert.Equal([]string{"abc", "a", "", "def"}, values)
+		assert.Equal([]string{"/1/String", "/1/Bars/0/Integers/1",
+			"/2/String"}, pointers)
+	}
+
+	// Errors returned by the function stop processing
+	errTest := errors.New("test")
+	nbItems := 0
+
+	err = DecodeArrayStream(strings.NewReader(data),
+		func(i int, foo *TestFoo, errs ValidationErrors) error {
+			nbItems++
+			if i == 1 {
+				return errTest
+			}
+
+			return nil
+		})
+	assert.ErrorIs(err, errTest)
+	assert.Equal(2, nbItems)
+
+	// Invalid documents
+	fn := func(i int, value interface{}, errs ValidationErrors) error {
+		return nil
+	}
+
+	var validationErrs ValidationErrors
+	err = DecodeArrayStream(strings.NewReader(`{"a": 1}`), fn)
+	if assert.ErrorAs(err, &validationErrs) {
+		assert.Equal("invalid_value_type", validationErrs[0].Code)
+	}
+
+	err = DecodeArrayStream(strings.NewReader(`[1, 2`), fn)
+	assert.Error(err)
+	assert.False(errors.As(err, &validationErrs))
+
+	assert.NoError(DecodeArrayStream(strings.NewReader(`[]`), fn))
+}
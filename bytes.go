@@ -0,0 +1,122 @@
+package ejson
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Bytes values are encoded as base64 strings using the standard alphabet and
+// padding (RFC 4648 4.). URLBytes values use the URL-safe alphabet without
+// padding (RFC 4648 5.); padding is accepted but not required when decoding.
+//
+// The maximum size of decoded data can be set for a structure field using the
+// "max_size" option of the ejson tag, e.g. `ejson:"max_size=1024"`.
+
+type Bytes []byte
+
+type URLBytes []byte
+
+var (
+	bytesType    = reflect.TypeOf(Bytes{})
+	urlBytesType = reflect.TypeOf(URLBytes{})
+)
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.StdEncoding.EncodeToString(b))
+}
+
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	return unmarshalBytes(data, base64.StdEncoding, bytesType, (*[]byte)(b))
+}
+
+func (b Bytes) checkJSONValue(value interface{}) *ValidationError {
+	return checkBytesValue(value, base64.StdEncoding, -1)
+}
+
+func (b URLBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *URLBytes) UnmarshalJSON(data []byte) error {
+	return unmarshalBytes(data, base64.RawURLEncoding, urlBytesType,
+		(*[]byte)(b))
+}
+
+func (b URLBytes) checkJSONValue(value interface{}) *ValidationError {
+	return checkBytesValue(value, base64.RawURLEncoding, -1)
+}
+
+func unmarshalBytes(data []byte, encoding *base64.Encoding, t reflect.Type, dest *[]byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	b, err := decodeBytes(value, encoding)
+	if err != nil {
+		return &json.UnmarshalTypeError{
+			Value: jsonValueDescription(value),
+			Type:  t,
+		}
+	}
+
+	*dest = b
+	return nil
+}
+
+func decodeBytes(value interface{}, encoding *base64.Encoding) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("value must be a base64 string")
+	}
+
+	if encoding == base64.RawURLEncoding {
+		s = strings.TrimRight(s, "=")
+	}
+
+	data, err := encoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 data")
+	}
+
+	return data, nil
+}
+
+func checkBytesValue(value interface{}, encoding *base64.Encoding, maxSize int) *ValidationError {
+	// Check the size before decoding to avoid allocating memory for data we
+	// are going to reject anyway.
+	if s, ok := value.(string); ok && maxSize >= 0 {
+		if encoding.DecodedLen(len(s)) > maxSize+2 {
+			return bytesTooLargeError(maxSize)
+		}
+	}
+
+	data, err := decodeBytes(value, encoding)
+	if err != nil {
+		return &ValidationError{
+			Code:    "invalid_base64_data",
+			Message: err.Error(),
+		}
+	}
+
+	if maxSize >= 0 && len(data) > maxSize {
+		return bytesTooLargeError(maxSize)
+	}
+
+	return nil
+}
+
+func bytesTooLargeError(maxSize int) *ValidationError {
+	return &ValidationError{
+		Code: "data_too_large",
+		Message: fmt.Sprintf("decoded data must contain %d bytes or less",
+			maxSize),
+	}
+}
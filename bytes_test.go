@@ -0,0 +1,44 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestFile struct {
+	Content Bytes    `json:"content" ejson:"max_size=8"`
+	Hash    URLBytes `json:"hash"`
+}
+
+func TestBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	var file TestFile
+	var validationErrs ValidationErrors
+
+	data := `{"content": "aGVsbG8=", "hash": "-_8"}`
+	if assert.NoError(Unmarshal([]byte(data), &file)) {
+		assert.Equal(Bytes("hello"), file.Content)
+		assert.Equal(URLBytes{0xfb, 0xff}, file.Hash)
+
+		data, err := json.Marshal(file)
+		if assert.NoError(err) {
+			assert.JSONEq(`{"content": "aGVsbG8=", "hash": "-_8"}`,
+				string(data))
+		}
+	}
+
+	data = `{"content": "aGVsbG8gd29ybGQ=", "hash": "+/8="}`
+	err := Unmarshal([]byte(data), &file)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(2, len(validationErrs)) {
+			assert.Equal("/content", validationErrs[0].Pointer.String())
+			assert.Equal("data_too_large", validationErrs[0].Code)
+
+			assert.Equal("/hash", validationErrs[1].Pointer.String())
+			assert.Equal("invalid_base64_data", validationErrs[1].Code)
+		}
+	}
+}
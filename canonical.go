@@ -0,0 +1,240 @@
+package ejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MarshalCanonical encodes a value using the JSON Canonicalization Scheme
+// (RFC 8785): no whitespace, object members sorted by the UTF-16 code units of
+// their name, numbers serialized as ECMAScript does and strings escaped as
+// little as possible.
+func MarshalCanonical(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var genericValue interface{}
+	if err := d.Decode(&genericValue); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonicalValue(&buf, genericValue); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonicalValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q: %w", v, err)
+		}
+
+		s, err := formatCanonicalNumber(f)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(s)
+
+	case float64:
+		s, err := formatCanonicalNumber(v)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(s)
+
+	case string:
+		writeCanonicalString(buf, v)
+
+	case []interface{}:
+		buf.WriteByte('[')
+
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeCanonicalValue(buf, element); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+
+			if err := writeCanonicalValue(buf, v[key]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+
+	default:
+		return &InvalidValueError{Value: value}
+	}
+
+	return nil
+}
+
+// RFC 8785 3.2.2.3. Serialization of Numbers
+//
+// The serialization is the one of the Number.prototype.toString method
+// defined in ECMAScript (ECMA-262 7.1.12.1).
+func formatCanonicalNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("invalid number %v", f)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	var buf strings.Builder
+
+	if f < 0 {
+		buf.WriteByte('-')
+		f = -f
+	}
+
+	// Use the shortest representation which rounds trip to obtain the
+	// digits and the exponent, so that f = 0.digits * 10^n.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+
+	mantissa, exponentString, _ := strings.Cut(s, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+
+	exponent, err := strconv.Atoi(exponentString)
+	if err != nil {
+		return "", fmt.Errorf("invalid exponent in %q", s)
+	}
+
+	k := len(digits)
+	n := exponent + 1
+
+	switch {
+	case k <= n && n <= 21:
+		buf.WriteString(digits)
+		buf.WriteString(strings.Repeat("0", n-k))
+
+	case 0 < n && n <= 21:
+		buf.WriteString(digits[:n])
+		buf.WriteByte('.')
+		buf.WriteString(digits[n:])
+
+	case -6 < n && n <= 0:
+		buf.WriteString("0.")
+		buf.WriteString(strings.Repeat("0", -n))
+		buf.WriteString(digits)
+
+	default:
+		buf.WriteByte(digits[0])
+
+		if k > 1 {
+			buf.WriteByte('.')
+			buf.WriteString(digits[1:])
+		}
+
+		buf.WriteByte('e')
+
+		if n-1 >= 0 {
+			buf.WriteByte('+')
+		}
+
+		buf.WriteString(strconv.Itoa(n - 1))
+	}
+
+	return buf.String(), nil
+}
+
+// RFC 8785 3.2.2.2. Serialization of Strings
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for _, c := range s {
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+			} else {
+				buf.WriteRune(c)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// RFC 8785 3.2.3. Sorting of Object Properties
+func lessUTF16(s1, s2 string) bool {
+	u1 := utf16.Encode([]rune(s1))
+	u2 := utf16.Encode([]rune(s2))
+
+	for i := 0; i < len(u1) && i < len(u2); i++ {
+		if u1[i] != u2[i] {
+			return u1[i] < u2[i]
+		}
+	}
+
+	return len(u1) < len(u2)
+}
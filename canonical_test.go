@@ -0,0 +1,55 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	assert := assert.New(t)
+
+	assertMarshal := func(expected string, value interface{}) {
+		t.Helper()
+
+		data, err := MarshalCanonical(value)
+		if assert.NoError(err) {
+			assert.Equal(expected, string(data))
+		}
+	}
+
+	// RFC 8785 3.2.2. Sample
+	var value interface{}
+	err := Unmarshal([]byte(`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`), &value)
+	if assert.NoError(err) {
+		assertMarshal(`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, value)
+	}
+
+	// RFC 8785 3.2.3. Sorting of Object Properties
+	assertMarshal("{\"\\r\":\"Carriage Return\",\"1\":\"One\","+
+		"\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O\","+
+		"\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji\","+
+		"\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		map[string]interface{}{
+			"\u20ac":     "Euro Sign",
+			"\r":         "Carriage Return",
+			"\ufb33":     "Hebrew Letter Dalet With Dagesh",
+			"1":          "One",
+			"\U0001f600": "Emoji",
+			"\u0080":     "Control",
+			"\u00f6":     "Latin Small Letter O",
+		})
+
+	// Numbers
+	assertMarshal(`[0,0,1,-1,1e+21,100000000000000000000,0.000001,1e-7,123.456,9007199254740991]`,
+		[]interface{}{0.0, -0.0, 1.0, -1.0, 1e21, 1e20, 0.000001, 1e-7,
+			123.456, 9007199254740991.0})
+
+	// Structures
+	assertMarshal(`{"Bar":{"Integers":[1,2]},"BarTable":null,"Bars":null,"String":"<>&","Tag":""}`,
+		&TestFoo{String: "<>&", Bar: &TestBar{Integers: []int{1, 2}}})
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"go.n16f.net/ejson"
+	"go.n16f.net/program"
+)
+
+func main() {
+	var c *program.Command
+
+	p := program.NewProgram("ejson", "utilities for json documents")
+
+	c = p.AddCommand("check",
+		"check that json documents are well-formed and optionally that "+
+			"they satisfy a rule document (json schema is not supported)",
+		cmdCheck)
+	c.AddOption("r", "rules", "path", "",
+		"the file containing the rule document used to validate documents")
+	c.AddTrailingArgument("path", "the files containing json documents")
+
+	c = p.AddCommand("format", "format a json document", cmdFormat)
+	c.AddOption("i", "indent", "string", "  ", "the indentation string")
+	c.AddFlag("s", "sort-keys", "sort the members of all objects")
+	c.AddOptionalArgument("path", "the file containing the json document")
+
+	c = p.AddCommand("canonicalize",
+		"print the canonical representation (RFC 8785) of a json document",
+		cmdCanonicalize)
+	c.AddOptionalArgument("path", "the file containing the json document")
+
+	c = p.AddCommand("diff",
+		"print a json patch (RFC 6902) transforming a json document into "+
+			"another one",
+		cmdDiff)
+	c.AddArgument("path1", "the file containing the original json document")
+	c.AddArgument("path2", "the file containing the modified json document")
+
+	c = p.AddCommand("patch", "apply a json patch (RFC 6902) to a json document",
+		cmdPatch)
+	c.AddArgument("patch", "the file containing the json patch")
+	c.AddOptionalArgument("path", "the file containing the json document")
+
+	c = p.AddCommand("find",
+		"extract and print the json value referenced by a pointer", cmdFind)
+	c.AddArgument("pointer", "the json pointer")
+	c.AddOptionalArgument("path", "the file containing the json document")
+
+	c = p.AddCommand("query",
+		"print the json values selected by a jsonpath query (RFC 9535)",
+		cmdQuery)
+	c.AddArgument("query", "the jsonpath query")
+	c.AddOptionalArgument("path", "the file containing the json document")
+
+	p.ParseCommandLine()
+	p.Run()
+}
+
+func cmdCheck(p *program.Program) {
+	filePaths := p.TrailingArgumentValues("path")
+
+	var rules *RuleDocument
+	if p.IsOptionSet("rules") {
+		rules = loadRuleDocument(p, p.OptionValue("rules"))
+	}
+
+	nbInvalidDocuments := 0
+
+	for _, filePath := range filePaths {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			p.Fatal("cannot read %q: %v", filePath, err)
+		}
+
+		var value interface{}
+		if err := decodeDocument(data, &value); err != nil {
+			p.Error("%s: %v", filePath, err)
+			nbInvalidDocuments++
+			continue
+		}
+
+		if rules != nil {
+			if err := rules.Check(value); err != nil {
+				p.Error("%s: %v", filePath, err)
+				nbInvalidDocuments++
+			}
+		}
+	}
+
+	if nbInvalidDocuments > 0 {
+		os.Exit(1)
+	}
+}
+
+func cmdFormat(p *program.Program) {
+	value := readDocument(p, p.OptionalArgumentValue("path"))
+
+	opts := ejson.MarshalOptions{
+		Indent:   p.OptionValue("indent"),
+		SortKeys: p.IsOptionSet("sort-keys"),
+	}
+
+	data, err := ejson.MarshalWith(value, opts)
+	if err != nil {
+		p.Fatal("cannot encode json value: %v", err)
+	}
+
+	writeData(p, data)
+}
+
+func cmdCanonicalize(p *program.Program) {
+	value := readDocument(p, p.OptionalArgumentValue("path"))
+
+	data, err := ejson.MarshalCanonical(value)
+	if err != nil {
+		p.Fatal("cannot encode json value: %v", err)
+	}
+
+	writeData(p, data)
+}
+
+func cmdDiff(p *program.Program) {
+	filePath1 := p.ArgumentValue("path1")
+	filePath2 := p.ArgumentValue("path2")
+
+	value1 := readDocument(p, &filePath1)
+	value2 := readDocument(p, &filePath2)
+
+	writeValue(p, ejson.Diff(value1, value2))
+}
+
+func cmdPatch(p *program.Program) {
+	patchFilePath := p.ArgumentValue("patch")
+
+	patchData, err := os.ReadFile(patchFilePath)
+	if err != nil {
+		p.Fatal("cannot read %q: %v", patchFilePath, err)
+	}
+
+	patch, err := ejson.ParsePatch(patchData)
+	if err != nil {
+		p.Fatal("invalid json patch: %v", err)
+	}
+
+	value := readDocument(p, p.OptionalArgumentValue("path"))
+
+	value2, err := patch.Apply(value)
+	if err != nil {
+		p.Fatal("cannot apply json patch: %v", err)
+	}
+
+	writeValue(p, value2)
+}
+
+func cmdFind(p *program.Program) {
+	pointerString := p.ArgumentValue("pointer")
+
+	var pointer ejson.Pointer
+	if err := pointer.Parse(pointerString); err != nil {
+		p.Fatal("invalid json pointer: %v", err)
+	}
+
+	value := readDocument(p, p.OptionalArgumentValue("path"))
+
+	value2, err := pointer.Resolve(value)
+	if err != nil {
+		p.Fatal("cannot resolve json pointer: %v", err)
+	}
+
+	writeValue(p, value2)
+}
+
+func cmdQuery(p *program.Program) {
+	queryString := p.ArgumentValue("query")
+
+	var query ejson.Query
+	if err := query.Parse(queryString); err != nil {
+		p.Fatal("invalid jsonpath query: %v", err)
+	}
+
+	value := readDocument(p, p.OptionalArgumentValue("path"))
+
+	values := []interface{}{}
+	for _, result := range query.Evaluate(value) {
+		values = append(values, result.Value)
+	}
+
+	writeValue(p, values)
+}
+
+func readDocument(p *program.Program, filePath *string) interface{} {
+	var data []byte
+	var err error
+
+	if filePath == nil || *filePath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			p.Fatal("cannot read standard input: %v", err)
+		}
+	} else {
+		data, err = os.ReadFile(*filePath)
+		if err != nil {
+			p.Fatal("cannot read %q: %v", *filePath, err)
+		}
+	}
+
+	var value interface{}
+	if err := decodeDocument(data, &value); err != nil {
+		p.Fatal("invalid json document: %v", err)
+	}
+
+	return value
+}
+
+// Decode a single json value, rejecting trailing data. Numbers are kept as
+// json.Number values so that they are not altered when written back.
+func decodeDocument(data []byte, value *interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(value); err != nil {
+		return err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after json value at offset %d",
+			d.InputOffset())
+	}
+
+	return nil
+}
+
+func writeValue(p *program.Program, value interface{}) {
+	data, err := ejson.MarshalWith(value, ejson.MarshalOptions{Indent: "  "})
+	if err != nil {
+		p.Fatal("cannot encode json value: %v", err)
+	}
+
+	writeData(p, data)
+}
+
+func writeData(p *program.Program, data []byte) {
+	data = append(data, '\n')
+
+	if _, err := os.Stdout.Write(data); err != nil {
+		p.Fatal("cannot write standard output: %v", err)
+	}
+}
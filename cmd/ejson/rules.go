@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.n16f.net/ejson"
+	"go.n16f.net/program"
+)
+
+// A rule document contains a list of rules, each rule applying a set of
+// checks to the values selected by a jsonpath query, e.g.:
+//
+//	{
+//	  "rules": [
+//	    {"query": "$.name", "required": true, "type": "string",
+//	     "min_length": 1, "pattern": "^[a-z]+$"},
+//	    {"query": "$.tags", "type": "array", "max_length": 10},
+//	    {"query": "$.tags[*]", "enum": ["a", "b", "c"]},
+//	    {"query": "$.age", "type": "integer", "minimum": 0},
+//	    {"query": "$.email", "format": "email"}
+//	  ]
+//	}
+//
+// Length constraints apply to strings and arrays. JSON Schema documents are
+// not supported.
+
+var ruleValueTypes = []string{
+	"null", "boolean", "number", "integer", "string", "array", "object",
+}
+
+var ruleFormats = []string{"email", "uri", "uuid", "domain_name"}
+
+type RuleDocument struct {
+	Rules []*Rule `json:"rules"`
+}
+
+type Rule struct {
+	Query     string        `json:"query"`
+	Required  bool          `json:"required"`
+	Type      string        `json:"type"`
+	Enum      []interface{} `json:"enum"`
+	MinLength *int          `json:"min_length"`
+	MaxLength *int          `json:"max_length"`
+	Minimum   *float64      `json:"minimum"`
+	Maximum   *float64      `json:"maximum"`
+	Pattern   string        `json:"pattern"`
+	Format    string        `json:"format"`
+
+	query   ejson.Query
+	pattern *regexp.Regexp
+}
+
+func (doc *RuleDocument) ValidateJSON(v *ejson.Validator) {
+	v.CheckObjectArray("rules", doc.Rules)
+}
+
+func (r *Rule) ValidateJSON(v *ejson.Validator) {
+	if v.CheckStringNotEmpty("query", r.Query) {
+		if err := r.query.Parse(r.Query); err != nil {
+			v.AddError("query", "invalid_query", "invalid jsonpath query: %v",
+				err)
+		}
+	}
+
+	if r.Type != "" {
+		v.CheckStringValue("type", r.Type, ruleValueTypes)
+	}
+
+	if r.Pattern != "" {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			v.AddError("pattern", "invalid_pattern",
+				"invalid regular expression: %v", err)
+		}
+
+		r.pattern = re
+	}
+
+	if r.Format != "" {
+		v.CheckStringValue("format", r.Format, ruleFormats)
+	}
+
+	v.CheckOptionalIntMin("min_length", r.MinLength, 0)
+	v.CheckOptionalIntMin("max_length", r.MaxLength, 0)
+}
+
+func loadRuleDocument(p *program.Program, filePath string) *RuleDocument {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		p.Fatal("cannot read %q: %v", filePath, err)
+	}
+
+	var doc RuleDocument
+	if err := ejson.Unmarshal(data, &doc); err != nil {
+		p.Fatal("invalid rule document %q: %v", filePath, err)
+	}
+
+	return &doc
+}
+
+// Check returns validation errors for all rules which are not satisfied by a
+// value.
+func (doc *RuleDocument) Check(value interface{}) error {
+	v := ejson.NewValidator()
+
+	for _, rule := range doc.Rules {
+		rule.check(v, value)
+	}
+
+	return v.Error()
+}
+
+func (r *Rule) check(v *ejson.Validator, value interface{}) {
+	results := r.query.Evaluate(value)
+
+	if len(results) == 0 && r.Required {
+		v.AddError(nil, "missing_value", "no value matches query %q",
+			r.Query)
+		return
+	}
+
+	for _, result := range results {
+		r.checkValue(v, result.Pointer, result.Value)
+	}
+}
+
+func (r *Rule) checkValue(v *ejson.Validator, token ejson.Pointer, value interface{}) {
+	if r.Type != "" && !ruleValueHasType(value, r.Type) {
+		v.AddError(token, "invalid_value_type", "value must be of type %s",
+			r.Type)
+		return
+	}
+
+	if len(r.Enum) > 0 {
+		found := false
+		for _, enumValue := range r.Enum {
+			if ejson.Equal(value, enumValue) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			v.AddError(token, "invalid_value", "value must be one of %s",
+				ruleEnumString(r.Enum))
+		}
+	}
+
+	switch tv := value.(type) {
+	case string:
+		if r.MinLength != nil {
+			v.CheckStringLengthMin(token, tv, *r.MinLength)
+		}
+
+		if r.MaxLength != nil {
+			v.CheckStringLengthMax(token, tv, *r.MaxLength)
+		}
+
+		if r.pattern != nil {
+			v.CheckStringMatch(token, tv, r.pattern)
+		}
+
+		switch r.Format {
+		case "email":
+			v.CheckEmailAddress(token, tv)
+		case "uri":
+			v.CheckStringURI(token, tv)
+		case "uuid":
+			v.CheckUUID(token, tv)
+		case "domain_name":
+			v.CheckDomainName(token, tv)
+		}
+
+	case []interface{}:
+		if r.MinLength != nil {
+			v.CheckArrayLengthMin(token, tv, *r.MinLength)
+		}
+
+		if r.MaxLength != nil {
+			v.CheckArrayLengthMax(token, tv, *r.MaxLength)
+		}
+
+	default:
+		if f, ok := ejson.AsNumberOK(value); ok {
+			if r.Minimum != nil {
+				v.CheckFloatMin(token, f, *r.Minimum)
+			}
+
+			if r.Maximum != nil {
+				v.CheckFloatMax(token, f, *r.Maximum)
+			}
+		}
+	}
+}
+
+func ruleValueHasType(value interface{}, typeName string) bool {
+	switch typeName {
+	case "null":
+		return ejson.IsNull(value)
+	case "boolean":
+		return ejson.IsBoolean(value)
+	case "number":
+		return ejson.IsNumber(value)
+	case "integer":
+		return ejson.IsInteger(value)
+	case "string":
+		return ejson.IsString(value)
+	case "array":
+		return ejson.IsArray(value)
+	case "object":
+		return ejson.IsObject(value)
+	}
+
+	return false
+}
+
+func ruleEnumString(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		data, err := ejson.MarshalWith(value, ejson.MarshalOptions{})
+		if err != nil {
+			parts[i] = fmt.Sprintf("%v", value)
+			continue
+		}
+
+		parts[i] = string(data)
+	}
+
+	return strings.Join(parts, ", ")
+}
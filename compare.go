@@ -0,0 +1,137 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type DifferenceKind string
+
+const (
+	DifferenceAdded       DifferenceKind = "added"
+	DifferenceRemoved     DifferenceKind = "removed"
+	DifferenceChanged     DifferenceKind = "changed"
+	DifferenceTypeChanged DifferenceKind = "type_changed"
+)
+
+// Value1 is nil for added values; Value2 is nil for removed values.
+type Difference struct {
+	Pointer Pointer
+	Kind    DifferenceKind
+	Value1  interface{}
+	Value2  interface{}
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DifferenceAdded:
+		return fmt.Sprintf("%s %q: %s", d.Kind, d.Pointer.String(),
+			differenceValueString(d.Value2))
+
+	case DifferenceRemoved:
+		return fmt.Sprintf("%s %q: %s", d.Kind, d.Pointer.String(),
+			differenceValueString(d.Value1))
+
+	default:
+		return fmt.Sprintf("%s %q: %s -> %s", d.Kind, d.Pointer.String(),
+			differenceValueString(d.Value1), differenceValueString(d.Value2))
+	}
+}
+
+func differenceValueString(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
+
+// Compare returns the list of differences between two generic JSON values.
+// Objects are compared member by member and arrays element by element.
+// Differences are ordered by pointer, object members being visited in
+// lexicographic order. An empty list means that values are equal.
+func Compare(v1, v2 interface{}) []Difference {
+	var diffs []Difference
+	compare(Pointer{}, v1, v2, &diffs)
+	return diffs
+}
+
+func compare(p Pointer, v1, v2 interface{}, diffs *[]Difference) {
+	add := func(p Pointer, kind DifferenceKind, v1, v2 interface{}) {
+		*diffs = append(*diffs, Difference{
+			Pointer: p,
+			Kind:    kind,
+			Value1:  v1,
+			Value2:  v2,
+		})
+	}
+
+	switch {
+	case IsObject(v1) && IsObject(v2):
+		obj1 := AsObject(v1)
+		obj2 := AsObject(v2)
+
+		names := make(map[string]interface{}, len(obj1)+len(obj2))
+		for name := range obj1 {
+			names[name] = nil
+		}
+		for name := range obj2 {
+			names[name] = nil
+		}
+
+		for _, name := range sortedMemberNames(names) {
+			value1, found1 := obj1[name]
+			value2, found2 := obj2[name]
+
+			switch {
+			case !found1:
+				add(p.Child(name), DifferenceAdded, nil, value2)
+			case !found2:
+				add(p.Child(name), DifferenceRemoved, value1, nil)
+			default:
+				compare(p.Child(name), value1, value2, diffs)
+			}
+		}
+
+	case IsArray(v1) && IsArray(v2):
+		a1 := AsArray(v1)
+		a2 := AsArray(v2)
+
+		for i := 0; i < len(a1) || i < len(a2); i++ {
+			switch {
+			case i >= len(a1):
+				add(p.Child(i), DifferenceAdded, nil, a2[i])
+			case i >= len(a2):
+				add(p.Child(i), DifferenceRemoved, a1[i], nil)
+			default:
+				compare(p.Child(i), a1[i], a2[i], diffs)
+			}
+		}
+
+	case jsonValueType(v1) != jsonValueType(v2):
+		add(p, DifferenceTypeChanged, v1, v2)
+
+	case !Equal(v1, v2):
+		add(p, DifferenceChanged, v1, v2)
+	}
+}
+
+func jsonValueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
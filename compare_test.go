@@ -0,0 +1,47 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	var v1, v2 interface{}
+	Unmarshal([]byte(`{"a": 1, "b": [1, 2, 3], "c": {"d": "x"}, "e": true,
+"f": null}`), &v1)
+	Unmarshal([]byte(`{"a": 2, "b": [1, 3], "c": {"d": "x", "g": [1]},
+"e": "true", "h": {}}`), &v2)
+
+	diffs := Compare(v1, v2)
+
+	assert.Equal([]Difference{
+		{NewPointer("a"), DifferenceChanged, 1.0, 2.0},
+		{NewPointer("b", 1), DifferenceChanged, 2.0, 3.0},
+		{NewPointer("b", 2), DifferenceRemoved, 3.0, nil},
+		{NewPointer("c", "g"), DifferenceAdded, nil, []interface{}{1.0}},
+		{NewPointer("e"), DifferenceTypeChanged, true, "true"},
+		{NewPointer("f"), DifferenceRemoved, nil, nil},
+		{NewPointer("h"), DifferenceAdded, nil, map[string]interface{}{}},
+	}, diffs)
+
+	var messages []string
+	for _, diff := range diffs[:5] {
+		messages = append(messages, diff.String())
+	}
+
+	assert.Equal([]string{
+		`changed "/a": 1 -> 2`,
+		`changed "/b/1": 2 -> 3`,
+		`removed "/b/2": 3`,
+		`added "/c/g": [1]`,
+		`type_changed "/e": true -> "true"`,
+	}, messages)
+
+	assert.Empty(Compare(v1, Clone(v1)))
+	assert.Equal([]Difference{
+		{Pointer{}, DifferenceTypeChanged, []interface{}{}, nil},
+	}, Compare([]interface{}{}, nil))
+}
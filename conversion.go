@@ -0,0 +1,154 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Conversion functions accept values of the requested type and values whose
+// representation can be converted without loss:
+//
+// - ToInt accepts integral numbers and strings containing a decimal integer.
+//
+// - ToFloat accepts numbers and strings containing a finite number.
+//
+// - ToBool accepts booleans, the strings "true" and "false", and the numbers
+// 0 and 1.
+//
+// - ToString accepts strings, numbers and booleans.
+//
+// Conversion failures are reported as *ConversionError values.
+
+type ConversionError struct {
+	Value interface{}
+	Type  string
+}
+
+func (err *ConversionError) Error() string {
+	return fmt.Sprintf("cannot convert %s to %s",
+		jsonValueDescription(err.Value), err.Type)
+}
+
+func ToInt(v interface{}) (int, error) {
+	convErr := &ConversionError{Value: v, Type: "integer"}
+
+	var f float64
+
+	switch tv := v.(type) {
+	case float64:
+		f = tv
+
+	case json.Number:
+		if i, err := strconv.ParseInt(string(tv), 10, 0); err == nil {
+			return int(i), nil
+		}
+
+		// Integers can also be written with a fractional part or an
+		// exponent, e.g. "1e3".
+		var err error
+		f, err = tv.Float64()
+		if err != nil {
+			return 0, convErr
+		}
+
+	case string:
+		i, err := strconv.Atoi(tv)
+		if err != nil {
+			return 0, convErr
+		}
+
+		return i, nil
+
+	default:
+		return 0, convErr
+	}
+
+	if f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, convErr
+	}
+
+	return int(f), nil
+}
+
+func ToFloat(v interface{}) (float64, error) {
+	convErr := &ConversionError{Value: v, Type: "number"}
+
+	var s string
+
+	switch tv := v.(type) {
+	case float64:
+		return tv, nil
+
+	case json.Number:
+		s = string(tv)
+
+	case string:
+		s = tv
+
+	default:
+		return 0.0, convErr
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0.0, convErr
+	}
+
+	return f, nil
+}
+
+func ToBool(v interface{}) (bool, error) {
+	switch tv := v.(type) {
+	case bool:
+		return tv, nil
+
+	case string:
+		switch tv {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+
+	case float64:
+		return floatToBool(v, tv)
+
+	case json.Number:
+		if f, err := tv.Float64(); err == nil {
+			return floatToBool(v, f)
+		}
+	}
+
+	return false, &ConversionError{Value: v, Type: "boolean"}
+}
+
+func floatToBool(v interface{}, f float64) (bool, error) {
+	switch f {
+	case 1.0:
+		return true, nil
+	case 0.0:
+		return false, nil
+	}
+
+	return false, &ConversionError{Value: v, Type: "boolean"}
+}
+
+func ToString(v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case string:
+		return tv, nil
+
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64), nil
+
+	case json.Number:
+		return string(tv), nil
+
+	case bool:
+		return strconv.FormatBool(tv), nil
+	}
+
+	return "", &ConversionError{Value: v, Type: "string"}
+}
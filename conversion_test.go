@@ -0,0 +1,84 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversion(t *testing.T) {
+	assert := assert.New(t)
+
+	assertConversion := func(expected interface{}, fn func(interface{}) (interface{}, error), v interface{}) {
+		t.Helper()
+
+		value, err := fn(v)
+		if assert.NoError(err, "%#v", v) {
+			assert.Equal(expected, value, "%#v", v)
+		}
+	}
+
+	assertConversionError := func(fn func(interface{}) (interface{}, error), v interface{}) {
+		t.Helper()
+
+		_, err := fn(v)
+
+		var convErr *ConversionError
+		assert.ErrorAs(err, &convErr, "%#v", v)
+	}
+
+	toInt := func(v interface{}) (interface{}, error) { return ToInt(v) }
+	toFloat := func(v interface{}) (interface{}, error) { return ToFloat(v) }
+	toBool := func(v interface{}) (interface{}, error) { return ToBool(v) }
+	toString := func(v interface{}) (interface{}, error) { return ToString(v) }
+
+	assertConversion(5, toInt, 5.0)
+	assertConversion(-5, toInt, "-5")
+	assertConversion(42, toInt, json.Number("42"))
+	assertConversion(1000, toInt, json.Number("1e3"))
+	assertConversion(2, toInt, json.Number("2.0"))
+	assertConversionError(toInt, json.Number("2.5"))
+	assertConversionError(toInt, json.Number("1e30"))
+	assertConversionError(toInt, 5.5)
+	assertConversionError(toInt, "5.0")
+	assertConversionError(toInt, 1e300)
+	assertConversionError(toInt, true)
+	assertConversionError(toInt, nil)
+
+	assertConversion(5.5, toFloat, 5.5)
+	assertConversion(5.5, toFloat, "5.5")
+	assertConversion(1e3, toFloat, json.Number("1e3"))
+	assertConversionError(toFloat, "NaN")
+	assertConversionError(toFloat, "foo")
+	assertConversionError(toFloat, []interface{}{})
+
+	assertConversion(true, toBool, true)
+	assertConversion(false, toBool, "false")
+	assertConversion(true, toBool, 1.0)
+	assertConversion(true, toBool, json.Number("1"))
+	assertConversion(false, toBool, json.Number("0"))
+	assertConversionError(toBool, json.Number("2"))
+	assertConversionError(toBool, "yes")
+	assertConversionError(toBool, 2.0)
+
+	assertConversion("x", toString, "x")
+	assertConversion("5", toString, 5.0)
+	assertConversion("0.25", toString, 0.25)
+	assertConversion("true", toString, true)
+	assertConversionError(toString, nil)
+	assertConversionError(toString, map[string]interface{}{})
+
+	_, err := ToInt("x")
+	assert.EqualError(err, `cannot convert string "x" to integer`)
+
+	s, ok := AsStringOK("x")
+	assert.True(ok)
+	assert.Equal("x", s)
+
+	_, ok = AsNumberOK("x")
+	assert.False(ok)
+
+	_, ok = AsObjectOK(nil)
+	assert.False(ok)
+}
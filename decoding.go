@@ -0,0 +1,415 @@
+package ejson
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Some features cannot be implemented on top of encoding/json alone. When the
+// destination type requires it, we decode the generic representation of the
+// document and use it to:
+//
+// - Check values of types provided by EJSON before the actual decoding, so
+//   that errors are reported with an exact pointer; encoding/json does not
+//   provide any reliable way to locate errors returned by UnmarshalJSON.
+//
+// - Fill the information the standard decoder has no way to provide after
+//   the actual decoding, e.g. members without any corresponding structure
+//   field.
+
+var decodingPassTypes sync.Map // reflect.Type -> bool
+
+var (
+	jsonUnmarshalerType  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	jsonValueCheckerType = reflect.TypeOf((*jsonValueChecker)(nil)).Elem()
+	jsonValueWrapperType = reflect.TypeOf((*jsonValueWrapper)(nil)).Elem()
+)
+
+type jsonValueChecker interface {
+	checkJSONValue(value interface{}) *ValidationError
+}
+
+// Values which decode their content with their own UnmarshalJSON method but
+// still have to be inspected, e.g. optional values.
+type jsonValueWrapper interface {
+	wrappedType() reflect.Type
+}
+
+func needsDecodingPass(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	if needed, found := decodingPassTypes.Load(t); found {
+		return needed.(bool)
+	}
+
+	needed := typeNeedsDecodingPass(t, make(map[reflect.Type]bool))
+	decodingPassTypes.Store(t, needed)
+
+	return needed
+}
+
+func typeNeedsDecodingPass(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	if t.Implements(jsonValueCheckerType) {
+		return true
+	}
+
+	if t.Kind() != reflect.Pointer && implementsUnmarshaler(t) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return typeNeedsDecodingPass(t.Elem(), visited)
+
+	case reflect.Struct:
+		info := getStructInfo(t)
+
+		if info.AnyFieldIndex != nil {
+			return true
+		}
+
+		for _, field := range info.Fields {
+			if field.TimeFormats != nil || field.Options.Has("max_size") {
+				return true
+			}
+
+			if typeNeedsDecodingPass(field.Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func implementsUnmarshaler(t reflect.Type) bool {
+	return t.Implements(jsonUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+}
+
+func runDecodingCheck(dest interface{}, value interface{}) error {
+	var errs ValidationErrors
+
+	decodingCheck(reflect.TypeOf(dest), value, Pointer{}, &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func decodingCheck(t reflect.Type, value interface{}, pointer Pointer, errs *ValidationErrors) {
+	if value == nil {
+		return
+	}
+
+	if t.Kind() != reflect.Pointer && t.Implements(jsonValueCheckerType) {
+		checker := reflect.Zero(t).Interface().(jsonValueChecker)
+		if err := checker.checkJSONValue(value); err != nil {
+			// The pointer of the error, if there is one, is relative to the
+			// value being checked.
+			err.Pointer = append(append(Pointer{}, pointer...), err.Pointer...)
+			*errs = append(*errs, err)
+		}
+
+		return
+	}
+
+	if t.Kind() != reflect.Pointer && implementsUnmarshaler(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		decodingCheck(t.Elem(), value, pointer, errs)
+
+	case reflect.Slice, reflect.Array:
+		if array, ok := value.([]interface{}); ok {
+			for i, element := range array {
+				decodingCheck(t.Elem(), element, pointer.Child(i), errs)
+			}
+		}
+
+	case reflect.Map:
+		if obj, ok := value.(map[string]interface{}); ok {
+			for _, key := range sortedMemberNames(obj) {
+				decodingCheck(t.Elem(), obj[key], pointer.Child(key), errs)
+			}
+		}
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		info := getStructInfo(t)
+
+		for _, name := range sortedMemberNames(obj) {
+			memberValue := obj[name]
+
+			field := info.Field(name)
+			if field == nil {
+				continue
+			}
+
+			if field.TimeFormats != nil {
+				checkTimeField(field.Type, memberValue, pointer.Child(name),
+					field.TimeFormats, errs)
+				continue
+			}
+
+			if checkFn := fieldValueChecker(field); checkFn != nil {
+				if memberValue == nil {
+					continue
+				}
+
+				if err := checkFn(memberValue); err != nil {
+					err.Pointer = pointer.Child(name)
+					*errs = append(*errs, err)
+				}
+
+				continue
+			}
+
+			decodingCheck(field.Type, memberValue, pointer.Child(name), errs)
+		}
+	}
+}
+
+// Some fields have options in their ejson tag which change the way their
+// value is checked.
+func fieldValueChecker(field *structField) func(interface{}) *ValidationError {
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	checkType := func(option string, types ...reflect.Type) {
+		for _, t2 := range types {
+			if t == t2 {
+				return
+			}
+		}
+
+		panic(fmt.Sprintf("ejson tag option %q cannot be used with field %s "+
+			"of type %v", option, field.GoName, field.Type))
+	}
+
+	switch {
+	case field.Options.Has("max_size"):
+		checkType("max_size", bytesType, urlBytesType)
+
+		maxSize, err := strconv.Atoi(field.Options["max_size"])
+		if err != nil || maxSize < 0 {
+			panic(fmt.Sprintf("invalid max_size value %q for field %s",
+				field.Options["max_size"], field.GoName))
+		}
+
+		encoding := base64.StdEncoding
+		if t == urlBytesType {
+			encoding = base64.RawURLEncoding
+		}
+
+		return func(value interface{}) *ValidationError {
+			return checkBytesValue(value, encoding, maxSize)
+		}
+	}
+
+	return nil
+}
+
+// Strict decoding reports object members which do not match any structure
+// field, unless the structure has a field tagged with `ejson:"any"`.
+func runUnknownMemberCheck(dest interface{}, value interface{}) ValidationErrors {
+	var errs ValidationErrors
+
+	unknownMemberCheck(reflect.TypeOf(dest), value, Pointer{}, &errs)
+
+	return errs
+}
+
+func unknownMemberCheck(t reflect.Type, value interface{}, pointer Pointer, errs *ValidationErrors) {
+	if value == nil {
+		return
+	}
+
+	if t.Kind() != reflect.Pointer && t.Implements(jsonValueWrapperType) {
+		wrapper := reflect.Zero(t).Interface().(jsonValueWrapper)
+		unknownMemberCheck(wrapper.wrappedType(), value, pointer, errs)
+		return
+	}
+
+	if t.Kind() != reflect.Pointer && implementsUnmarshaler(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		unknownMemberCheck(t.Elem(), value, pointer, errs)
+
+	case reflect.Slice, reflect.Array:
+		if array, ok := value.([]interface{}); ok {
+			for i, element := range array {
+				unknownMemberCheck(t.Elem(), element, pointer.Child(i), errs)
+			}
+		}
+
+	case reflect.Map:
+		if obj, ok := value.(map[string]interface{}); ok {
+			for _, key := range sortedMemberNames(obj) {
+				unknownMemberCheck(t.Elem(), obj[key], pointer.Child(key), errs)
+			}
+		}
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		info := getStructInfo(t)
+
+		for _, name := range sortedMemberNames(obj) {
+			field := info.Field(name)
+			if field == nil {
+				if info.AnyFieldIndex == nil {
+					*errs = append(*errs, &ValidationError{
+						Pointer: pointer.Child(name),
+						Code:    "unknown_member",
+						Message: "unknown member",
+					})
+				}
+
+				continue
+			}
+
+			unknownMemberCheck(field.Type, obj[name], pointer.Child(name), errs)
+		}
+	}
+}
+
+func runDecodingPass(dest interface{}, value interface{}) {
+	decodingPass(reflect.ValueOf(dest), value)
+}
+
+func decodingPass(dest reflect.Value, value interface{}) {
+	if !dest.IsValid() {
+		return
+	}
+
+	t := dest.Type()
+
+	if t.Kind() != reflect.Pointer && implementsUnmarshaler(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if !dest.IsNil() {
+			decodingPass(dest.Elem(), value)
+		}
+
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+
+		for i := 0; i < dest.Len() && i < len(array); i++ {
+			decodingPass(dest.Index(i), array[i])
+		}
+
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok || t.Key().Kind() != reflect.String ||
+			!needsDecodingPass(t.Elem()) {
+			return
+		}
+
+		// Map elements are not addressable, so we have to work on a copy.
+		iter := dest.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+
+			elem := reflect.New(t.Elem()).Elem()
+			elem.Set(iter.Value())
+
+			decodingPass(elem, obj[key.String()])
+
+			dest.SetMapIndex(key, elem)
+		}
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		decodeStructPass(dest, obj)
+	}
+}
+
+func decodeStructPass(dest reflect.Value, obj map[string]interface{}) {
+	info := getStructInfo(dest.Type())
+
+	var anyMembers map[string]interface{}
+
+	for name, memberValue := range obj {
+		field := info.Field(name)
+		if field == nil {
+			if info.AnyFieldIndex != nil {
+				if anyMembers == nil {
+					anyMembers = make(map[string]interface{})
+				}
+
+				anyMembers[name] = memberValue
+			}
+
+			continue
+		}
+
+		fieldValue := fieldByIndex(dest, field.Index)
+		if !fieldValue.IsValid() {
+			continue
+		}
+
+		if field.TimeFormats != nil {
+			decodeTimePass(fieldValue, memberValue, field.TimeFormats)
+			continue
+		}
+
+		decodingPass(fieldValue, memberValue)
+	}
+
+	if anyMembers != nil && dest.CanSet() {
+		anyField := settableFieldByIndex(dest, info.AnyFieldIndex)
+		if anyField.IsValid() {
+			anyField.Set(reflect.ValueOf(anyMembers))
+		}
+	}
+}
+
+func sortedMemberNames(obj map[string]interface{}) []string {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,113 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Duration values are encoded as strings using the format of
+// time.Duration.String (e.g. "1h30m0s"). They are decoded from either strings
+// accepted by time.ParseDuration or numbers of seconds.
+type Duration time.Duration
+
+var durationType = reflect.TypeOf(Duration(0))
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	d2, err := parseDuration(value)
+	if err != nil {
+		return &json.UnmarshalTypeError{
+			Value: jsonValueDescription(value),
+			Type:  durationType,
+		}
+	}
+
+	*d = d2
+	return nil
+}
+
+func (d Duration) checkJSONValue(value interface{}) *ValidationError {
+	if _, err := parseDuration(value); err != nil {
+		return &ValidationError{
+			Code:    "invalid_duration",
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func parseDuration(value interface{}) (Duration, error) {
+	switch v := value.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration format")
+		}
+
+		return Duration(d), nil
+
+	case float64:
+		nanoseconds := v * float64(time.Second)
+		if math.IsNaN(nanoseconds) ||
+			nanoseconds > math.MaxInt64 || nanoseconds < math.MinInt64 {
+			return 0, fmt.Errorf("invalid duration")
+		}
+
+		return Duration(nanoseconds), nil
+
+	default:
+		return 0, fmt.Errorf("duration must be a string or a number")
+	}
+}
+
+func (v *Validator) CheckDurationMin(token interface{}, d, min time.Duration) bool {
+	if d >= min {
+		return true
+	}
+
+	v.AddError(token, "duration_too_short",
+		"duration must be greater or equal to %v", min)
+	return false
+}
+
+func (v *Validator) CheckDurationMax(token interface{}, d, max time.Duration) bool {
+	if d <= max {
+		return true
+	}
+
+	v.AddError(token, "duration_too_long",
+		"duration must be lower or equal to %v", max)
+	return false
+}
+
+func (v *Validator) CheckDurationMinMax(token interface{}, d, min, max time.Duration) bool {
+	if !v.CheckDurationMin(token, d, min) {
+		return false
+	}
+
+	return v.CheckDurationMax(token, d, max)
+}
@@ -0,0 +1,46 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestTimeouts struct {
+	Timeouts []Duration `json:"timeouts"`
+}
+
+func TestDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	var value TestTimeouts
+	var validationErrs ValidationErrors
+
+	data := `{"timeouts": ["1h30m", 2.5, 0]}`
+	if assert.NoError(Unmarshal([]byte(data), &value)) {
+		assert.Equal([]Duration{
+			Duration(90 * time.Minute),
+			Duration(2500 * time.Millisecond),
+			Duration(0),
+		}, value.Timeouts)
+
+		data, err := json.Marshal(value)
+		if assert.NoError(err) {
+			assert.JSONEq(`{"timeouts": ["1h30m0s", "2.5s", "0s"]}`,
+				string(data))
+		}
+	}
+
+	err := Unmarshal([]byte(`{"timeouts": ["10s", "foo", true]}`), &value)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(2, len(validationErrs)) {
+			assert.Equal("/timeouts/1", validationErrs[0].Pointer.String())
+			assert.Equal("invalid_duration", validationErrs[0].Code)
+
+			assert.Equal("/timeouts/2", validationErrs[1].Pointer.String())
+			assert.Equal("invalid_duration", validationErrs[1].Code)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package ejsontest provides assertions to test validation code in unit
+// tests.
+package ejsontest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"go.n16f.net/ejson"
+)
+
+// UpdateGoldenFilesEnvVar is the name of the environment variable which, if
+// set to a non-empty value, causes AssertGoldenErrors to write golden files
+// instead of comparing them.
+const UpdateGoldenFilesEnvVar = "EJSONTEST_UPDATE"
+
+// ValidationErrors returns the validation errors contained in an error, or
+// nil if there are none.
+func ValidationErrors(err error) ejson.ValidationErrors {
+	var errs ejson.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	return errs
+}
+
+// AssertValid checks that a value is valid according to ejson.Validate.
+func AssertValid(t testing.TB, value interface{}) bool {
+	t.Helper()
+
+	if err := ejson.Validate(value); err != nil {
+		t.Errorf("value is invalid: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// AssertNoError checks that an error, typically returned by ejson.Unmarshal,
+// is nil.
+func AssertNoError(t testing.TB, err error) bool {
+	t.Helper()
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// AssertErrorAt checks that an error contains a validation error with a
+// specific pointer and code.
+func AssertErrorAt(t testing.TB, err error, pointer, code string) bool {
+	t.Helper()
+
+	if err == nil {
+		t.Errorf("missing validation error %q at %q", code, pointer)
+		return false
+	}
+
+	errs := ValidationErrors(err)
+	if errs == nil {
+		t.Errorf("error is not a validation error: %v", err)
+		return false
+	}
+
+	for _, err := range errs {
+		if err.Pointer.String() == pointer && err.Code == code {
+			return true
+		}
+	}
+
+	t.Errorf("missing validation error %q at %q in %v", code, pointer, err)
+	return false
+}
+
+// AssertNoErrorAt checks that an error does not contain any validation error
+// for a specific pointer.
+func AssertNoErrorAt(t testing.TB, err error, pointer string) bool {
+	t.Helper()
+
+	for _, err := range ValidationErrors(err) {
+		if err.Pointer.String() == pointer {
+			t.Errorf("unexpected validation error %q at %q: %s",
+				err.Code, pointer, err.Message)
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssertGoldenErrors checks that the validation errors contained in an error
+// match the content of a golden file. Errors are serialized as an indented
+// JSON array, or as an empty array if there are no validation errors.
+//
+// If the UpdateGoldenFilesEnvVar environment variable is set, the golden
+// file is written instead.
+func AssertGoldenErrors(t testing.TB, err error, path string) bool {
+	t.Helper()
+
+	if err != nil && ValidationErrors(err) == nil {
+		t.Errorf("error is not a validation error: %v", err)
+		return false
+	}
+
+	errs := ValidationErrors(err)
+	if errs == nil {
+		errs = ejson.ValidationErrors{}
+	}
+
+	data, err := ejson.MarshalWith(errs, ejson.MarshalOptions{Indent: "  "})
+	if err != nil {
+		t.Errorf("cannot encode validation errors: %v", err)
+		return false
+	}
+
+	data = append(data, '\n')
+
+	if os.Getenv(UpdateGoldenFilesEnvVar) != "" {
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Errorf("cannot write %q: %v", path, err)
+			return false
+		}
+
+		return true
+	}
+
+	expectedData, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("cannot read %q: %v", path, err)
+		return false
+	}
+
+	if !bytes.Equal(data, expectedData) {
+		t.Errorf("validation errors do not match %q:\n%s", path, data)
+		return false
+	}
+
+	return true
+}
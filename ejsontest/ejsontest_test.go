@@ -0,0 +1,98 @@
+package ejsontest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"go.n16f.net/ejson"
+)
+
+type testUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (u *testUser) ValidateJSON(v *ejson.Validator) {
+	v.CheckStringNotEmpty("name", u.Name)
+	v.CheckEmailAddress("email", u.Email)
+}
+
+// Record failures instead of failing the test
+type testTB struct {
+	testing.TB
+
+	failures []string
+}
+
+func (t *testTB) Helper() {}
+
+func (t *testTB) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	validUser := testUser{Name: "bob", Email: "bob@example.com"}
+	invalidUser := testUser{Email: "foo"}
+
+	var user testUser
+	err := ejson.Unmarshal([]byte(`{"email": "foo"}`), &user)
+
+	assertResult := func(expected bool, fn func(t testing.TB) bool) {
+		t.Helper()
+
+		var tb testTB
+		result := fn(&tb)
+
+		if result != expected {
+			t.Errorf("assertion returned %v", result)
+		}
+
+		if result && len(tb.failures) > 0 {
+			t.Errorf("successful assertion reported failures: %v",
+				tb.failures)
+		} else if !result && len(tb.failures) == 0 {
+			t.Errorf("failed assertion did not report any failure")
+		}
+	}
+
+	assertResult(true, func(t testing.TB) bool {
+		return AssertValid(t, &validUser)
+	})
+	assertResult(false, func(t testing.TB) bool {
+		return AssertValid(t, &invalidUser)
+	})
+
+	assertResult(true, func(t testing.TB) bool {
+		return AssertErrorAt(t, err, "/email", "invalid_email_address")
+	})
+	assertResult(true, func(t testing.TB) bool {
+		return AssertErrorAt(t, err, "/name", "missing_or_empty_string")
+	})
+	assertResult(false, func(t testing.TB) bool {
+		return AssertErrorAt(t, err, "/email", "missing_or_empty_string")
+	})
+	assertResult(false, func(t testing.TB) bool {
+		return AssertErrorAt(t, nil, "/email", "invalid_email_address")
+	})
+	assertResult(false, func(t testing.TB) bool {
+		return AssertErrorAt(t, fmt.Errorf("foo"), "", "invalid_value")
+	})
+
+	assertResult(true, func(t testing.TB) bool {
+		return AssertNoErrorAt(t, err, "/foo")
+	})
+	assertResult(false, func(t testing.TB) bool {
+		return AssertNoErrorAt(t, err, "/email")
+	})
+
+	assertResult(true, func(t testing.TB) bool {
+		return AssertGoldenErrors(t, err, "testdata/errors.json")
+	})
+
+	if os.Getenv(UpdateGoldenFilesEnvVar) == "" {
+		assertResult(false, func(t testing.TB) bool {
+			return AssertGoldenErrors(t, nil, "testdata/errors.json")
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ejson
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrConflictingValues = errors.New("conflicting values")
+
+type unflattenNode map[string]interface{}
+
+// Flatten returns a map associating the string representation of the pointer
+// of each leaf value with the value itself. Leaf values are null values,
+// numbers, strings, booleans, and empty arrays and objects.
+func Flatten(v interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	flatten(Pointer{}, v, values)
+	return values
+}
+
+func flatten(p Pointer, v interface{}, values map[string]interface{}) {
+	switch tv := v.(type) {
+	case []interface{}:
+		if len(tv) > 0 {
+			for i, element := range tv {
+				flatten(p.Child(i), element, values)
+			}
+
+			return
+		}
+
+	case map[string]interface{}:
+		if len(tv) > 0 {
+			for name, value := range tv {
+				flatten(p.Child(name), value, values)
+			}
+
+			return
+		}
+	}
+
+	values[p.String()] = v
+}
+
+// Unflatten is the inverse of Flatten. Since pointers do not carry type
+// information, a container is rebuilt as an array if its tokens are exactly
+// the indexes 0 to N-1, and as an object otherwise.
+func Unflatten(values map[string]interface{}) (interface{}, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var root interface{}
+	rootSet := false
+
+	for _, key := range keys {
+		var p Pointer
+		if err := p.Parse(key); err != nil {
+			return nil, fmt.Errorf("invalid json pointer %q: %w", key, err)
+		}
+
+		conflictErr := &PointerError{Pointer: p, Err: ErrConflictingValues}
+
+		if len(p) == 0 {
+			if rootSet {
+				return nil, conflictErr
+			}
+
+			root = values[key]
+			rootSet = true
+			continue
+		}
+
+		if !rootSet {
+			root = unflattenNode{}
+			rootSet = true
+		}
+
+		node, ok := root.(unflattenNode)
+		if !ok {
+			return nil, conflictErr
+		}
+
+		for _, token := range p[:len(p)-1] {
+			child, found := node[token]
+			if !found {
+				child = unflattenNode{}
+				node[token] = child
+			}
+
+			node, ok = child.(unflattenNode)
+			if !ok {
+				return nil, conflictErr
+			}
+		}
+
+		token := p[len(p)-1]
+		if _, found := node[token]; found {
+			return nil, conflictErr
+		}
+
+		node[token] = values[key]
+	}
+
+	return unflattenValue(root), nil
+}
+
+func unflattenValue(v interface{}) interface{} {
+	node, ok := v.(unflattenNode)
+	if !ok {
+		return v
+	}
+
+	isArray := true
+	for token := range node {
+		i, ok := Token(token).ArrayIndex()
+		if !ok || i >= len(node) {
+			isArray = false
+			break
+		}
+	}
+
+	if isArray {
+		array := make([]interface{}, len(node))
+		for token, value := range node {
+			i, _ := Token(token).ArrayIndex()
+			array[i] = unflattenValue(value)
+		}
+
+		return array
+	}
+
+	obj := make(map[string]interface{}, len(node))
+	for token, value := range node {
+		obj[token] = unflattenValue(value)
+	}
+
+	return obj
+}
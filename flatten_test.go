@@ -0,0 +1,79 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"a": [1, {"b": null}], "c/d": {"e": "x"},
+"f": [], "g": {}, "h": true}`), &value)
+
+	values := Flatten(value)
+	assert.Equal(map[string]interface{}{
+		"/a/0":    1.0,
+		"/a/1/b":  nil,
+		"/c~1d/e": "x",
+		"/f":      []interface{}{},
+		"/g":      map[string]interface{}{},
+		"/h":      true,
+	}, values)
+
+	value2, err := Unflatten(values)
+	if assert.NoError(err) {
+		assert.Equal(value, value2)
+	}
+
+	assert.Equal(map[string]interface{}{"": 42.0}, Flatten(42.0))
+
+	value2, err = Unflatten(map[string]interface{}{"": 42.0})
+	if assert.NoError(err) {
+		assert.Equal(42.0, value2)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	assert := assert.New(t)
+
+	value, err := Unflatten(map[string]interface{}{
+		"/a/1":  "y",
+		"/a/0":  "x",
+		"/b/0":  1.0,
+		"/b/2":  2.0,
+		"/c/01": 3.0,
+	})
+	if assert.NoError(err) {
+		assert.Equal(map[string]interface{}{
+			"a": []interface{}{"x", "y"},
+			"b": map[string]interface{}{"0": 1.0, "2": 2.0},
+			"c": map[string]interface{}{"01": 3.0},
+		}, value)
+	}
+
+	value, err = Unflatten(map[string]interface{}{})
+	if assert.NoError(err) {
+		assert.Nil(value)
+	}
+
+	assertConflict := func(expectedPointer string, values map[string]interface{}) {
+		t.Helper()
+
+		_, err := Unflatten(values)
+
+		var pointerErr *PointerError
+		if assert.ErrorAs(err, &pointerErr) {
+			assert.ErrorIs(err, ErrConflictingValues)
+			assert.Equal(expectedPointer, pointerErr.Pointer.String())
+		}
+	}
+
+	assertConflict("/a/b", map[string]interface{}{"/a": 1.0, "/a/b": 2.0})
+	assertConflict("/a", map[string]interface{}{"": 1.0, "/a": 2.0})
+
+	_, err = Unflatten(map[string]interface{}{"a": 1.0})
+	assert.ErrorIs(err, ErrInvalidPointerFormat)
+}
@@ -6,6 +6,7 @@ require (
 	github.com/stretchr/testify v1.8.3
 	go.n16f.net/program v0.0.0-20240707135706-c2e994295489
 	go.n16f.net/uuid v0.0.0-20240707135755-e4fd26b968ad
+	golang.org/x/text v0.22.0
 )
 
 require (
@@ -0,0 +1,128 @@
+package ejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const DefaultMaxRequestBodySize = 1024 * 1024
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrRequestBodyTooLarge  = errors.New("request body too large")
+)
+
+// ReadRequestBody reads the body of a request with a size limit of
+// DefaultMaxRequestBodySize and decodes it with ReadRequestBodyWithLimit.
+func ReadRequestBody(r *http.Request, dest interface{}) error {
+	return ReadRequestBodyWithLimit(r, dest, DefaultMaxRequestBodySize)
+}
+
+// ReadRequestBodyWithLimit reads the body of a request, decodes it and
+// validates it as done by Unmarshal. Decoding is strict: object members which
+// do not match any structure field are reported as validation errors with
+// the "unknown_member" code, unless they are collected by a field tagged with
+// `ejson:"any"`.
+//
+// The request must have a JSON media type, i.e. "application/json" or a type
+// with the "+json" suffix, otherwise ErrUnsupportedMediaType is returned.
+// ErrRequestBodyTooLarge is returned if the body is larger than maxSize
+// bytes. The body must contain a single JSON value.
+func ReadRequestBodyWithLimit(r *http.Request, dest interface{}, maxSize int64) error {
+	if err := checkRequestMediaType(r); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("cannot read request body: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return ErrRequestBodyTooLarge
+	}
+
+	// Check the whole body before decoding it so that invalid bodies are
+	// not reported to the metrics recorder.
+	d := json.NewDecoder(bytes.NewReader(data))
+
+	var value json.RawMessage
+	if err := d.Decode(&value); err != nil {
+		return ConvertUnmarshallingError(err)
+	}
+
+	if err := checkTrailingData(d); err != nil {
+		return err
+	}
+
+	opts := UnmarshalOptions{DisallowUnknownMembers: true}
+	return UnmarshalWith(value, dest, opts)
+}
+
+func checkTrailingData(d *json.Decoder) error {
+	if _, err := d.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after JSON value at offset %d",
+			d.InputOffset())
+	}
+
+	return nil
+}
+
+func checkRequestMediaType(r *http.Request) error {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return fmt.Errorf("%w: missing content type", ErrUnsupportedMediaType)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return fmt.Errorf("%w: invalid content type %q",
+			ErrUnsupportedMediaType, header)
+	}
+
+	if mediaType != "application/json" &&
+		!strings.HasSuffix(mediaType, "+json") {
+		return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, mediaType)
+	}
+
+	return nil
+}
+
+// ValidationErrorsResponse is the document sent by WriteValidationErrors.
+type ValidationErrorsResponse struct {
+	Message string           `json:"message"`
+	Errors  ValidationErrors `json:"errors"`
+}
+
+// WriteValidationErrors sends a response with the 422 status and a
+// ValidationErrorsResponse document.
+func WriteValidationErrors(w http.ResponseWriter, errs ValidationErrors) error {
+	if errs == nil {
+		errs = ValidationErrors{}
+	}
+
+	data, err := json.Marshal(ValidationErrorsResponse{
+		Message: "invalid data",
+		Errors:  errs,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot encode validation errors: %w", err)
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "application/json")
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("cannot write response body: %w", err)
+	}
+
+	return nil
+}
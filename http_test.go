@@ -0,0 +1,152 @@
+package ejson
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRequestBody(t *testing.T) {
+	assert := assert.New(t)
+
+	newRequest := func(contentType, body string) *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		return req
+	}
+
+	var foo TestFoo
+	err := ReadRequestBody(newRequest("application/json; charset=utf-8",
+		`{"String": "abc"}`), &foo)
+	if assert.NoError(err) {
+		assert.Equal("abc", foo.String)
+	}
+
+	foo = TestFoo{}
+	assert.NoError(ReadRequestBody(newRequest("application/merge-patch+json",
+		`{"String": "abc"}`), &foo))
+
+	assert.ErrorIs(ReadRequestBody(newRequest("", `{"String": "abc"}`), &foo),
+		ErrUnsupportedMediaType)
+	assert.ErrorIs(ReadRequestBody(newRequest("text/plain",
+		`{"String": "abc"}`), &foo), ErrUnsupportedMediaType)
+
+	assert.ErrorIs(ReadRequestBodyWithLimit(newRequest("application/json",
+		`{"String": "abcdef"}`), &foo, 10), ErrRequestBodyTooLarge)
+
+	assert.Error(ReadRequestBody(newRequest("application/json",
+		`{"String": "abc"} {}`), &foo))
+
+	var validationErrs ValidationErrors
+	err = ReadRequestBody(newRequest("application/json", `{"String": "a"}`),
+		&foo)
+	if assert.ErrorAs(err, &validationErrs) {
+		assert.Equal("/String", validationErrs[0].Pointer.String())
+	}
+}
+
+func TestReadRequestBodyUnknownMembers(t *testing.T) {
+	assert := assert.New(t)
+
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	type Document struct {
+		Items    []Item                 `json:"items"`
+		Optional Optional[Item]         `json:"optional"`
+		Extra    *TestExtensible        `json:"extra"`
+		Labels   map[string]interface{} `json:"labels"`
+	}
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var doc Document
+	err := ReadRequestBody(newRequest(`{"items": [{"name": "a"}],
+"extra": {"name": "b", "x-foo": 1}, "labels": {"c": {"d": 2}}}`), &doc)
+	if assert.NoError(err) {
+		assert.Equal([]Item{{Name: "a"}}, doc.Items)
+		assert.Equal("b", doc.Extra.Name)
+	}
+
+	var validationErrs ValidationErrors
+
+	doc = Document{}
+	err = ReadRequestBody(newRequest(`{"items": [{"name": "a"},
+{"name": "b", "foo": 1}], "optional": {"bar": true}, "baz": null,
+"extra": {"name": "c", "children": [{"qux": 2}]}}`), &doc)
+	if assert.ErrorAs(err, &validationErrs) {
+		pointers := make([]string, len(validationErrs))
+		for i, err := range validationErrs {
+			pointers[i] = err.Pointer.String()
+		}
+
+		assert.Equal([]string{"/baz", "/items/1/foo", "/optional/bar"},
+			pointers)
+		for _, err := range validationErrs {
+			assert.Equal("unknown_member", err.Code)
+		}
+	}
+
+	var foo TestFoo
+	err = ReadRequestBody(newRequest(`{"String": "a", "foo": 1}`), &foo)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(2, len(validationErrs)) {
+			assert.Equal("/foo", validationErrs[0].Pointer.String())
+			assert.Equal("unknown_member", validationErrs[0].Code)
+			assert.Equal("/String", validationErrs[1].Pointer.String())
+		}
+	}
+
+	// Bodies with trailing data are not counted as validated documents
+	var recorder testMetricsRecorder
+	SetMetricsRecorder(&recorder)
+	defer SetMetricsRecorder(nil)
+
+	assert.Error(ReadRequestBody(newRequest(`{"String": "abc"} {}`),
+		&foo))
+	assert.Equal(0, len(recorder.results))
+
+	assert.NoError(ReadRequestBody(newRequest(`{"String": "abc"}`), &foo))
+	assert.Equal(1, len(recorder.results))
+	assert.ErrorIs(ReadRequestBodyWithLimit(newRequest(
+		`{"String": "abcdef"}`), &foo, 10), ErrRequestBodyTooLarge)
+}
+
+func TestWriteValidationErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	w := httptest.NewRecorder()
+
+	err := WriteValidationErrors(w, ValidationErrors{
+		&ValidationError{
+			Pointer: NewPointer("a", 1),
+			Code:    "invalid_value",
+			Message: "invalid value",
+		},
+	})
+	if assert.NoError(err) {
+		assert.Equal(http.StatusUnprocessableEntity, w.Code)
+		assert.Equal("application/json", w.Header().Get("Content-Type"))
+
+		var response ValidationErrorsResponse
+		if assert.NoError(json.Unmarshal(w.Body.Bytes(), &response)) {
+			assert.Equal("invalid data", response.Message)
+			if assert.Equal(1, len(response.Errors)) {
+				assert.Equal("/a/1", response.Errors[0].Pointer.String())
+				assert.Equal("invalid_value", response.Errors[0].Code)
+			}
+		}
+	}
+}
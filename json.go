@@ -5,28 +5,127 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
-// It would be nice to have a DecodeStrict() function which would use
-// (*json.Decoder).DisallowUnknownFields(). Infortunately, the errors produced
-// this way are not structured and therefore unusable. There is no way out
-// this whole mess without rewriting an unmarshaller based on
-// (*json.Decoder).Token(). This would increase memory pressure, but this is
-// irrelevant for most use cases and would allow much better error reporting.
-
 func Unmarshal(data []byte, dest interface{}) error {
+	return UnmarshalWith(data, dest, UnmarshalOptions{})
+}
+
+// UnmarshalOptions control the way data are decoded. They must be used
+// instead of the options of json.Decoder: these cannot be read back, and are
+// therefore lost when the data have to be decoded in several passes, e.g. for
+// types such as Time or structures with a field tagged with `ejson:"any"`.
+type UnmarshalOptions struct {
+	// Decode numbers stored in interface values as json.Number values, as
+	// done by (*json.Decoder).UseNumber().
+	UseNumber bool
+
+	// Report object members which do not match any structure field as
+	// validation errors with the "unknown_member" code. Contrary to
+	// (*json.Decoder).DisallowUnknownFields(), errors have an exact pointer,
+	// and members collected by fields tagged with `ejson:"any"` are
+	// accepted.
+	DisallowUnknownMembers bool
+}
+
+func UnmarshalWith(data []byte, dest interface{}, opts UnmarshalOptions) error {
 	d := json.NewDecoder(bytes.NewReader(data))
-	return UnmarshalDecoder(d, dest)
+	return UnmarshalDecoderWith(d, dest, opts)
 }
 
+// UnmarshalDecoder decodes the next value read by a decoder and validates
+// it. Validation errors, including those caused by values of the wrong type,
+// are reported to the metrics recorder if there is one.
 func UnmarshalDecoder(d *json.Decoder, dest interface{}) error {
-	if err := d.Decode(dest); err != nil {
+	return UnmarshalDecoderWith(d, dest, UnmarshalOptions{})
+}
+
+func UnmarshalDecoderWith(d *json.Decoder, dest interface{}, opts UnmarshalOptions) error {
+	err := unmarshalDecoder(d, dest, opts)
+	recordValidation(err)
+	return err
+}
+
+// As for all non-pointer values in encoding/json, null is ignored when
+// decoding values of EJSON types. Nullable values are the exception since
+// they record the presence of null.
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// Decode and validate data without reporting anything to the metrics
+// recorder; used by functions which are not entry points for documents.
+func unmarshal(data []byte, dest interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	return unmarshalDecoder(d, dest, UnmarshalOptions{})
+}
+
+func unmarshalDecoder(d *json.Decoder, dest interface{}, opts UnmarshalOptions) error {
+	if opts.UseNumber {
+		d.UseNumber()
+	}
+
+	if !opts.DisallowUnknownMembers && !needsDecodingPass(reflect.TypeOf(dest)) {
+		if err := d.Decode(dest); err != nil {
+			return ConvertUnmarshallingError(err)
+		}
+
+		return validate(dest)
+	}
+
+	// If we need additional passes, we have to keep the data around and
+	// decode them again with the same options.
+	var data json.RawMessage
+	if err := d.Decode(&data); err != nil {
+		return ConvertUnmarshallingError(err)
+	}
+
+	var value interface{}
+	if err := decodeRawMessage(data, &value, opts); err != nil {
+		return ConvertUnmarshallingError(err)
+	}
+
+	var errs ValidationErrors
+	if opts.DisallowUnknownMembers {
+		errs = runUnknownMemberCheck(dest, value)
+	}
+
+	if err := runDecodingCheck(dest, value); err != nil {
+		return append(errs, err.(ValidationErrors)...)
+	}
+
+	if err := decodeRawMessage(data, dest, opts); err != nil {
 		return ConvertUnmarshallingError(err)
 	}
 
-	return Validate(dest)
+	runDecodingPass(dest, value)
 
+	if err := validate(dest); err != nil {
+		validationErrs, ok := err.(ValidationErrors)
+		if !ok {
+			return err
+		}
+
+		errs = append(errs, validationErrs...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func decodeRawMessage(data json.RawMessage, dest interface{}, opts UnmarshalOptions) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	if opts.UseNumber {
+		d.UseNumber()
+	}
+
+	return d.Decode(dest)
 }
 
 func UnmarshalReader(r io.Reader, dest interface{}) error {
@@ -34,6 +133,51 @@ func UnmarshalReader(r io.Reader, dest interface{}) error {
 	return UnmarshalDecoder(d, dest)
 }
 
+type MarshalOptions struct {
+	Prefix string
+	Indent string
+
+	EscapeHTML bool
+
+	// Sort the members of all objects, including those resulting from the
+	// encoding of structures.
+	SortKeys bool
+}
+
+func MarshalWith(value interface{}, opts MarshalOptions) ([]byte, error) {
+	if opts.SortKeys {
+		// Maps are always encoded with sorted keys, so the simplest way to
+		// sort structure members is to use the generic representation of the
+		// value.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+
+		var genericValue interface{}
+		if err := d.Decode(&genericValue); err != nil {
+			return nil, err
+		}
+
+		value = genericValue
+	}
+
+	var buf bytes.Buffer
+
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(opts.EscapeHTML)
+	e.SetIndent(opts.Prefix, opts.Indent)
+
+	if err := e.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
 func ConvertUnmarshallingError(err error) error {
 	switch err2 := err.(type) {
 	case *json.UnmarshalTypeError:
@@ -66,3 +210,24 @@ func ConvertUnmarshallingError(err error) error {
 		return err
 	}
 }
+
+func jsonValueDescription(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number " + strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return "number " + v.String()
+	case string:
+		return "string " + strconv.Quote(v)
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
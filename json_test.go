@@ -0,0 +1,211 @@
+package ejson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestExtensible struct {
+	Name       string                 `json:"name"`
+	Children   []*TestExtensible      `json:"children"`
+	Extensions map[string]interface{} `json:"-" ejson:"any"`
+}
+
+func (e *TestExtensible) ValidateJSON(v *Validator) {
+	for name := range e.Extensions {
+		v.Check(name, strings.HasPrefix(name, "x-"), "unknown_member",
+			"unknown member")
+	}
+
+	v.CheckObjectArray("children", e.Children)
+}
+
+func TestUnmarshalAnyMembers(t *testing.T) {
+	assert := assert.New(t)
+
+	var value TestExtensible
+	var validationErrs ValidationErrors
+
+	data := `{"name": "a", "x-foo": 1, "children": [{"name": "b"},
+{"name": "c", "x-bar": {"x": true}}]}`
+
+	if assert.NoError(Unmarshal([]byte(data), &value)) {
+		assert.Equal("a", value.Name)
+		assert.Equal(map[string]interface{}{"x-foo": 1.0}, value.Extensions)
+
+		if assert.Equal(2, len(value.Children)) {
+			assert.Nil(value.Children[0].Extensions)
+			assert.Equal(map[string]interface{}{
+				"x-bar": map[string]interface{}{"x": true},
+			}, value.Children[1].Extensions)
+		}
+	}
+
+	data = `{"name": "a", "children": [{"name": "b", "foo": 42}]}`
+
+	err := Unmarshal([]byte(data), &value)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/children/0/foo",
+				validationErrs[0].Pointer.String())
+			assert.Equal("unknown_member", validationErrs[0].Code)
+		}
+	}
+}
+
+func TestUnmarshalWith(t *testing.T) {
+	assert := assert.New(t)
+
+	var value TestExtensible
+	var validationErrs ValidationErrors
+
+	opts := UnmarshalOptions{UseNumber: true}
+
+	data := `{"name": "a", "x-n": 12345678901234567890}`
+	if assert.NoError(UnmarshalWith([]byte(data), &value, opts)) {
+		assert.Equal(map[string]interface{}{
+			"x-n": json.Number("12345678901234567890"),
+		}, value.Extensions)
+	}
+
+	var values []interface{}
+	d := json.NewDecoder(strings.NewReader(`[1.5]`))
+	if assert.NoError(UnmarshalDecoderWith(d, &values, opts)) {
+		assert.Equal([]interface{}{json.Number("1.5")}, values)
+	}
+
+	type Event struct {
+		Name string `json:"name"`
+		Date Time   `json:"date"`
+	}
+
+	opts = UnmarshalOptions{DisallowUnknownMembers: true}
+
+	data = `{"name": "a", "date": 1700000000, "foo": 1}`
+	err := UnmarshalWith([]byte(data), &Event{}, opts)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/foo", validationErrs[0].Pointer.String())
+			assert.Equal("unknown_member", validationErrs[0].Code)
+		}
+	}
+
+	data = `{"String": "a", "Bars": [{"foo": 1}]}`
+	err = UnmarshalWith([]byte(data), &TestFoo{}, opts)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(2, len(validationErrs)) {
+			assert.Equal("/Bars/0/foo", validationErrs[0].Pointer.String())
+			assert.Equal("unknown_member", validationErrs[0].Code)
+			assert.Equal("/String", validationErrs[1].Pointer.String())
+		}
+	}
+
+	// Members collected by a field tagged as "any" are not unknown
+	value = TestExtensible{}
+	data = `{"name": "a", "x-foo": 1}`
+	if assert.NoError(UnmarshalWith([]byte(data), &value, opts)) {
+		assert.Equal(map[string]interface{}{"x-foo": 1.0}, value.Extensions)
+	}
+}
+
+func TestUnmarshalNull(t *testing.T) {
+	assert := assert.New(t)
+
+	type Value struct {
+		Time     Time          `json:"time"`
+		Duration Duration      `json:"duration"`
+		Bytes    Bytes         `json:"bytes"`
+		Optional Optional[int] `json:"optional"`
+		Nullable Nullable[int] `json:"nullable"`
+	}
+
+	value := Value{
+		Time:     Time{Time: time.Unix(1700000000, 0)},
+		Duration: Duration(time.Second),
+		Bytes:    Bytes("abc"),
+	}
+
+	err := Unmarshal([]byte(`{"time": null, "duration": null, "bytes": null,
+"optional": null, "nullable": null}`), &value)
+	if assert.NoError(err) {
+		assert.Equal(int64(1700000000), value.Time.Unix())
+		assert.Equal(Duration(time.Second), value.Duration)
+		assert.Equal(Bytes("abc"), value.Bytes)
+		assert.False(value.Optional.IsSet())
+		assert.True(value.Nullable.IsNull())
+	}
+}
+
+func TestMarshalWith(t *testing.T) {
+	assert := assert.New(t)
+
+	value := TestFoo{
+		String: "<a>",
+		Bar:    &TestBar{Integers: []int{1}},
+	}
+
+	assertMarshal := func(expected string, opts MarshalOptions) {
+		t.Helper()
+
+		data, err := MarshalWith(value, opts)
+		if assert.NoError(err) {
+			assert.Equal(expected, string(data))
+		}
+	}
+
+	assertMarshal(`{"String":"<a>","Bar":{"Integers":[1]},"Bars":null,`+
+		`"BarTable":null,"Tag":""}`, MarshalOptions{})
+
+	assertMarshal(`{"String":"\u003ca\u003e","Bar":{"Integers":[1]},`+
+		`"Bars":null,"BarTable":null,"Tag":""}`,
+		MarshalOptions{EscapeHTML: true})
+
+	assertMarshal(`{
+  "Bar": {
+    "Integers": [
+      1
+    ]
+  },
+  "BarTable": null,
+  "Bars": null,
+  "String": "<a>",
+  "Tag": ""
+}`, MarshalOptions{Indent: "  ", SortKeys: true})
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data, err := json.Marshal(benchmarkFoo())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var foo TestFoo
+		if err := Unmarshal(data, &foo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalAnyMembers(b *testing.B) {
+	data := []byte(`{"name": "a", "x-foo": 1, "children": [{"name": "b"},
+{"name": "c", "x-bar": {"x": true}}, {"name": "d", "children": [
+{"name": "e", "x-baz": [1, 2, 3]}]}]}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var value TestExtensible
+		if err := Unmarshal(data, &value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package ejson
+
+// JSONC is the informal JSON dialect used by many configuration files: it
+// supports JavaScript comments and trailing commas in arrays and objects.
+//
+// We convert JSONC data to JSON by replacing comments and trailing commas with
+// whitespaces. Line breaks are preserved, so that both byte offsets and line
+// numbers reported in errors are valid for the original data.
+
+func UnmarshalJSONC(data []byte, dest interface{}) error {
+	return Unmarshal(StripJSONC(data), dest)
+}
+
+func StripJSONC(data []byte) []byte {
+	const (
+		stateDefault = iota
+		stateString
+		stateStringEscape
+		stateLineComment
+		stateBlockComment
+	)
+
+	output := make([]byte, len(data))
+	copy(output, data)
+
+	state := stateDefault
+	pendingComma := -1
+
+	// The last character outside of strings, comments and whitespaces; only
+	// commas following a value can be trailing commas.
+	var last byte
+
+	blank := func(i int) {
+		if c := output[i]; c != '\n' && c != '\r' {
+			output[i] = ' '
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		switch state {
+		case stateDefault:
+			switch {
+			case c == '/' && i+1 < len(data) && data[i+1] == '/':
+				state = stateLineComment
+				blank(i)
+
+			case c == '/' && i+1 < len(data) && data[i+1] == '*':
+				state = stateBlockComment
+				blank(i)
+				blank(i + 1)
+				i++
+
+			case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+
+			default:
+				if pendingComma >= 0 && (c == '}' || c == ']') {
+					output[pendingComma] = ' '
+				}
+				pendingComma = -1
+
+				switch c {
+				case ',':
+					switch last {
+					case 0, '[', '{', ',', ':':
+					default:
+						pendingComma = i
+					}
+				case '"':
+					state = stateString
+				}
+
+				last = c
+			}
+
+		case stateString:
+			switch c {
+			case '\\':
+				state = stateStringEscape
+			case '"':
+				state = stateDefault
+			}
+
+		case stateStringEscape:
+			state = stateString
+
+		case stateLineComment:
+			if c == '\n' {
+				state = stateDefault
+			} else {
+				blank(i)
+			}
+
+		case stateBlockComment:
+			if c == '*' && i+1 < len(data) && data[i+1] == '/' {
+				state = stateDefault
+				blank(i)
+				blank(i + 1)
+				i++
+			} else {
+				blank(i)
+			}
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,43 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripJSONC(t *testing.T) {
+	assert := assert.New(t)
+
+	assertStrip := func(expected, data string) {
+		t.Helper()
+
+		output := StripJSONC([]byte(data))
+		assert.Equal(len(data), len(output), data)
+		assert.Equal(expected, string(output), data)
+	}
+
+	assertStrip(`{"a": 1}`, `{"a": 1}`)
+	assertStrip(`{"a": 1  }`, `{"a": 1, }`)
+	assertStrip(`[1, 2 ]`, `[1, 2,]`)
+	assertStrip(`[1,    2   ]`, `[1,/**/2 , ]`)
+	assertStrip("{\"a\": 1     \n}", "{\"a\": 1 // x\n}")
+	assertStrip("{     \n  \"a\": 1}", "{ /* x\n*/\"a\": 1}")
+	assertStrip(`{"a": "//,}"}`, `{"a": "//,}"}`)
+	assertStrip(`{"a": "\"/**/"}`, `{"a": "\"/**/"}`)
+	assertStrip(`[[1 ] ]`, `[[1,],]`)
+	assertStrip(`["a", {} ]`, `["a", {},]`)
+	assertStrip(`[,]`, `[,]`)
+	assertStrip(`{,}`, `{,}`)
+	assertStrip(`[1,,]`, `[1,,]`)
+	assertStrip(`{"a":,}`, `{"a":,}`)
+
+	var value interface{}
+	err := json.Unmarshal(StripJSONC([]byte("// x\n{\"a\": ]")), &value)
+
+	var syntaxErr *json.SyntaxError
+	if assert.ErrorAs(err, &syntaxErr) {
+		assert.Equal(int64(12), syntaxErr.Offset)
+	}
+}
@@ -0,0 +1,82 @@
+package ejson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LinesDecoder reads JSON Lines (also known as NDJSON) data, i.e. a sequence
+// of JSON values separated by newline characters. Empty lines are ignored.
+//
+// Each record is decoded and validated as done by Unmarshal. Errors
+// affecting a single record are returned as *LineError values; the caller can
+// keep calling Decode to read the following records.
+type LinesDecoder struct {
+	r    *bufio.Reader
+	line int
+	eof  bool
+}
+
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+}
+
+func (err *LineError) Unwrap() error {
+	return err.Err
+}
+
+func NewLinesDecoder(r io.Reader) *LinesDecoder {
+	return &LinesDecoder{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Line returns the number of the line containing the last record read,
+// starting at 1.
+func (d *LinesDecoder) Line() int {
+	return d.line
+}
+
+// Decode reads the next record and stores it into dest, returning io.EOF if
+// there is no more record to read.
+func (d *LinesDecoder) Decode(dest interface{}) error {
+	for {
+		if d.eof {
+			return io.EOF
+		}
+
+		data, err := d.r.ReadBytes('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			d.eof = true
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		d.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		if err := Unmarshal(data, dest); err != nil {
+			return &LineError{Line: d.line, Err: err}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package ejson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinesDecoder(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `{"String": "abc"}
+
+{"String": "a"}
+{"String": 42}
+{"String":
+{"String": "def"}`
+
+	d := NewLinesDecoder(strings.NewReader(data))
+
+	var foo TestFoo
+	var lineErr *LineError
+	var validationErrs ValidationErrors
+
+	if assert.NoError(d.Decode(&foo)) {
+		assert.Equal(1, d.Line())
+		assert.Equal("abc", foo.String)
+	}
+
+	foo = TestFoo{}
+	err := d.Decode(&foo)
+	if assert.ErrorAs(err, &lineErr) {
+		assert.Equal(3, lineErr.Line)
+
+		if assert.ErrorAs(err, &validationErrs) {
+			assert.Equal("/String", validationErrs[0].Pointer.String())
+			assert.Equal("string_too_short", validationErrs[0].Code)
+		}
+	}
+
+	foo = TestFoo{}
+	err = d.Decode(&foo)
+	if assert.ErrorAs(err, &lineErr) {
+		assert.Equal(4, lineErr.Line)
+
+		if assert.ErrorAs(err, &validationErrs) {
+			assert.Equal("invalid_value_type", validationErrs[0].Code)
+		}
+	}
+
+	foo = TestFoo{}
+	err = d.Decode(&foo)
+	if assert.ErrorAs(err, &lineErr) {
+		assert.Equal(5, lineErr.Line)
+		assert.False(errors.As(err, &validationErrs))
+	}
+
+	foo = TestFoo{}
+	if assert.NoError(d.Decode(&foo)) {
+		assert.Equal(6, d.Line())
+		assert.Equal("def", foo.String)
+	}
+
+	assert.Equal(io.EOF, d.Decode(&foo))
+}
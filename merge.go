@@ -0,0 +1,125 @@
+package ejson
+
+import "fmt"
+
+type MergeArrayStrategy string
+
+const (
+	// Arrays of the overlay replace arrays of the base value (default).
+	MergeArraysReplace MergeArrayStrategy = "replace"
+
+	// Elements of arrays of the overlay are appended to elements of arrays
+	// of the base value.
+	MergeArraysConcat MergeArrayStrategy = "concat"
+
+	// Elements of arrays of the overlay are merged with the elements of
+	// arrays of the base value at the same index.
+	MergeArraysByIndex MergeArrayStrategy = "merge_by_index"
+)
+
+type MergeNullStrategy string
+
+const (
+	// Null values of the overlay replace values of the base (default).
+	MergeNullsReplace MergeNullStrategy = "replace"
+
+	// Null values of the overlay are ignored.
+	MergeNullsIgnore MergeNullStrategy = "ignore"
+
+	// Null object members of the overlay delete the corresponding members of
+	// the base value.
+	MergeNullsDelete MergeNullStrategy = "delete"
+)
+
+type MergeOptions struct {
+	Arrays MergeArrayStrategy
+	Nulls  MergeNullStrategy
+}
+
+// Merge returns the result of the deep merge of the overlay into the base
+// value. Objects are merged recursively; arrays and null values are handled
+// according to options; any other value of the overlay replaces the value of
+// the base. Neither the base value nor the overlay are modified.
+func Merge(base, overlay interface{}, opts MergeOptions) interface{} {
+	switch opts.Arrays {
+	case "", MergeArraysReplace, MergeArraysConcat, MergeArraysByIndex:
+	default:
+		panic(fmt.Sprintf("unknown array merge strategy %q", opts.Arrays))
+	}
+
+	switch opts.Nulls {
+	case "", MergeNullsReplace, MergeNullsIgnore, MergeNullsDelete:
+	default:
+		panic(fmt.Sprintf("unknown null merge strategy %q", opts.Nulls))
+	}
+
+	return merge(base, overlay, &opts)
+}
+
+func merge(base, overlay interface{}, opts *MergeOptions) interface{} {
+	if overlay == nil {
+		if opts.Nulls == MergeNullsIgnore {
+			return Clone(base)
+		}
+
+		return nil
+	}
+
+	switch ov := overlay.(type) {
+	case []interface{}:
+		bv, ok := base.([]interface{})
+		if !ok {
+			break
+		}
+
+		switch opts.Arrays {
+		case MergeArraysConcat:
+			array := make([]interface{}, 0, len(bv)+len(ov))
+			array = append(array, AsArray(Clone(bv))...)
+			array = append(array, AsArray(Clone(ov))...)
+			return array
+
+		case MergeArraysByIndex:
+			n := max(len(bv), len(ov))
+
+			array := make([]interface{}, n)
+			for i := 0; i < n; i++ {
+				switch {
+				case i >= len(ov):
+					array[i] = Clone(bv[i])
+				case i >= len(bv):
+					array[i] = Clone(ov[i])
+				default:
+					array[i] = merge(bv[i], ov[i], opts)
+				}
+			}
+
+			return array
+		}
+
+	case map[string]interface{}:
+		bv, ok := base.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		obj := AsObject(Clone(bv))
+
+		for key, value := range ov {
+			if value == nil && opts.Nulls == MergeNullsDelete {
+				delete(obj, key)
+				continue
+			}
+
+			if baseValue, found := bv[key]; found {
+				obj[key] = merge(baseValue, value, opts)
+			} else if value != nil || opts.Nulls != MergeNullsIgnore {
+				obj[key] = Clone(value)
+			}
+		}
+
+		return obj
+	}
+
+	return Clone(overlay)
+}
@@ -0,0 +1,64 @@
+package ejson
+
+// ApplyMergePatch applies a JSON merge patch (RFC 7386) to a target value and
+// returns the result. The target value is not modified.
+func ApplyMergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return Clone(patch)
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if ok {
+		targetObj = AsObject(Clone(targetObj))
+	} else {
+		targetObj = make(map[string]interface{})
+	}
+
+	for name, value := range patchObj {
+		if value == nil {
+			delete(targetObj, name)
+			continue
+		}
+
+		targetObj[name] = ApplyMergePatch(targetObj[name], value)
+	}
+
+	return targetObj
+}
+
+// CreateMergePatch returns a JSON merge patch (RFC 7386) which transforms the
+// original value into the modified value when applied to it.
+//
+// Merge patches cannot represent object members whose value is null; such
+// members in the modified value are deleted when the patch is applied.
+func CreateMergePatch(original, modified interface{}) interface{} {
+	originalObj, ok1 := original.(map[string]interface{})
+	modifiedObj, ok2 := modified.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return Clone(modified)
+	}
+
+	patch := make(map[string]interface{})
+
+	for name := range originalObj {
+		if _, found := modifiedObj[name]; !found {
+			patch[name] = nil
+		}
+	}
+
+	for name, value := range modifiedObj {
+		originalValue, found := originalObj[name]
+		if found && Equal(originalValue, value) {
+			continue
+		}
+
+		if IsObject(originalValue) && IsObject(value) {
+			patch[name] = CreateMergePatch(originalValue, value)
+		} else {
+			patch[name] = Clone(value)
+		}
+	}
+
+	return patch
+}
@@ -0,0 +1,87 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	decode := func(s string) interface{} {
+		t.Helper()
+
+		var value interface{}
+		if err := Unmarshal([]byte(s), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	assertApply := func(expected, target, patch string) {
+		t.Helper()
+
+		targetValue := decode(target)
+		targetCopy := Clone(targetValue)
+
+		value := ApplyMergePatch(targetValue, decode(patch))
+		assert.Equal(decode(expected), value, patch)
+		assert.Equal(targetCopy, targetValue, patch)
+	}
+
+	// Examples from RFC 7386 Appendix A
+	assertApply(`{"a":"c"}`, `{"a":"b"}`, `{"a":"c"}`)
+	assertApply(`{"a":"b","b":"c"}`, `{"a":"b"}`, `{"b":"c"}`)
+	assertApply(`{}`, `{"a":"b"}`, `{"a":null}`)
+	assertApply(`{"b":"c"}`, `{"a":"b","b":"c"}`, `{"a":null}`)
+	assertApply(`{"a":"c"}`, `{"a":["b"]}`, `{"a":"c"}`)
+	assertApply(`{"a":["b"]}`, `{"a":"c"}`, `{"a":["b"]}`)
+	assertApply(`{"a":{"b":"d"}}`, `{"a":{"b":"c"}}`,
+		`{"a":{"b":"d","c":null}}`)
+	assertApply(`{"a":[1]}`, `{"a":[{"b":"c"}]}`, `{"a":[1]}`)
+	assertApply(`["c","d"]`, `["a","b"]`, `["c","d"]`)
+	assertApply(`["c"]`, `{"a":"b"}`, `["c"]`)
+	assertApply(`null`, `{"a":"foo"}`, `null`)
+	assertApply(`"bar"`, `{"a":"foo"}`, `"bar"`)
+	assertApply(`{"e":null,"a":1}`, `{"e":null}`, `{"a":1}`)
+	assertApply(`{"a":1}`, `[1,2]`, `{"a":1,"b":null}`)
+	assertApply(`{"a":{"bb":{}}}`, `{}`, `{"a":{"bb":{"ccc":null}}}`)
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	decode := func(s string) interface{} {
+		t.Helper()
+
+		var value interface{}
+		if err := Unmarshal([]byte(s), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	assertCreate := func(expected, original, modified string) {
+		t.Helper()
+
+		originalValue := decode(original)
+		modifiedValue := decode(modified)
+
+		patch := CreateMergePatch(originalValue, modifiedValue)
+		if assert.Equal(decode(expected), patch, modified) {
+			assert.Equal(modifiedValue,
+				ApplyMergePatch(originalValue, patch), modified)
+		}
+	}
+
+	assertCreate(`{}`, `{"a":1}`, `{"a":1}`)
+	assertCreate(`{"a":2,"c":3}`, `{"a":1,"b":[1]}`, `{"a":2,"b":[1],"c":3}`)
+	assertCreate(`{"b":null}`, `{"a":1,"b":2}`, `{"a":1}`)
+	assertCreate(`{"a":{"c":null,"d":1}}`, `{"a":{"b":1,"c":2}}`,
+		`{"a":{"b":1,"d":1}}`)
+	assertCreate(`{"a":[2]}`, `{"a":[1,2]}`, `{"a":[2]}`)
+	assertCreate(`[1]`, `{"a":1}`, `[1]`)
+}
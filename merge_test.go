@@ -0,0 +1,49 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	var base, overlay interface{}
+	Unmarshal([]byte(`{"a": 1, "b": {"c": [1, {"x": 1}], "d": "x"}, "e": 2}`),
+		&base)
+	Unmarshal([]byte(`{"a": null, "b": {"c": [{"y": 2}], "f": true}, "g": null}`),
+		&overlay)
+
+	assertMerge := func(expected string, opts MergeOptions) {
+		t.Helper()
+
+		var expectedValue interface{}
+		if err := Unmarshal([]byte(expected), &expectedValue); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(expectedValue, Merge(base, overlay, opts))
+	}
+
+	assertMerge(`{"a": null, "b": {"c": [{"y": 2}], "d": "x", "f": true},
+"e": 2, "g": null}`, MergeOptions{})
+
+	assertMerge(`{"a": 1, "b": {"c": [1, {"x": 1}, {"y": 2}], "d": "x",
+"f": true}, "e": 2}`,
+		MergeOptions{Arrays: MergeArraysConcat, Nulls: MergeNullsIgnore})
+
+	assertMerge(`{"b": {"c": [{"y": 2}, {"x": 1}], "d": "x", "f": true},
+"e": 2}`,
+		MergeOptions{Arrays: MergeArraysByIndex, Nulls: MergeNullsDelete})
+
+	// Inputs must not be modified
+	assert.Equal(1.0, AsObject(base)["a"])
+	assert.Equal(2, len(AsArray(AsObject(AsObject(base)["b"])["c"])))
+
+	assert.Equal("x", Merge(map[string]interface{}{}, "x", MergeOptions{}))
+
+	assert.Panics(func() {
+		Merge(base, overlay, MergeOptions{Arrays: "foo"})
+	})
+}
@@ -0,0 +1,231 @@
+package ejson
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricsRecorder receives the outcome of the validation of each document
+// processed by Validate, DecodeValue and the Unmarshal functions.
+type MetricsRecorder interface {
+	// RecordValidation is called with the validation errors of a document,
+	// or with nil if the document is valid. It can be called concurrently.
+	RecordValidation(errs ValidationErrors)
+}
+
+// MetricsErrorCountBuckets are the upper bounds of the buckets used to count
+// documents by number of validation errors.
+var MetricsErrorCountBuckets = []int{0, 1, 2, 5, 10, 20, 50, 100}
+
+type metricsRecorderHolder struct {
+	recorder MetricsRecorder
+}
+
+var metricsRecorder atomic.Pointer[metricsRecorderHolder]
+
+// SetMetricsRecorder sets the recorder called for each validated document.
+// Calling it with nil disables metrics.
+func SetMetricsRecorder(recorder MetricsRecorder) {
+	if recorder == nil {
+		metricsRecorder.Store(nil)
+		return
+	}
+
+	metricsRecorder.Store(&metricsRecorderHolder{recorder: recorder})
+}
+
+// Report the result of a validation to the metrics recorder. Errors which are
+// not validation errors (e.g. syntax errors) are not reported.
+func recordValidation(err error) {
+	holder := metricsRecorder.Load()
+	if holder == nil {
+		return
+	}
+
+	if err == nil {
+		holder.recorder.RecordValidation(nil)
+		return
+	}
+
+	if errs, ok := err.(ValidationErrors); ok {
+		holder.recorder.RecordValidation(errs)
+	}
+}
+
+// Return the index of the bucket for a number of errors, len(buckets) being
+// the index of the +Inf bucket.
+func errorCountBucket(buckets []int, n int) int {
+	return sort.SearchInts(buckets, n)
+}
+
+// ExpvarMetricsRecorder publishes validation metrics as an expvar.Map
+// containing:
+//
+//   - "documents": the number of validated documents;
+//   - "invalid_documents": the number of documents with validation errors;
+//   - "errors": a map containing the number of errors by code;
+//   - "error_counts": a map containing the number of documents by bucket of
+//     number of errors, the key of each bucket being its upper bound.
+type ExpvarMetricsRecorder struct {
+	Map *expvar.Map
+
+	documents        expvar.Int
+	invalidDocuments expvar.Int
+	errors           expvar.Map
+	errorCounts      expvar.Map
+
+	bounds  []int
+	buckets []string
+}
+
+// NewExpvarMetricsRecorder creates a recorder and publishes its metrics with
+// the given name. As expvar.Publish, it panics if the name is already used.
+func NewExpvarMetricsRecorder(name string) *ExpvarMetricsRecorder {
+	r := ExpvarMetricsRecorder{
+		Map: expvar.NewMap(name),
+	}
+
+	r.Map.Set("documents", &r.documents)
+	r.Map.Set("invalid_documents", &r.invalidDocuments)
+	r.Map.Set("errors", &r.errors)
+	r.Map.Set("error_counts", &r.errorCounts)
+
+	r.bounds = append([]int{}, MetricsErrorCountBuckets...)
+	for _, bound := range r.bounds {
+		r.buckets = append(r.buckets, strconv.Itoa(bound))
+	}
+	r.buckets = append(r.buckets, "+Inf")
+
+	return &r
+}
+
+func (r *ExpvarMetricsRecorder) RecordValidation(errs ValidationErrors) {
+	r.documents.Add(1)
+
+	if len(errs) > 0 {
+		r.invalidDocuments.Add(1)
+	}
+
+	for _, err := range errs {
+		r.errors.Add(err.Code, 1)
+	}
+
+	bucket := errorCountBucket(r.bounds, len(errs))
+	r.errorCounts.Add(r.buckets[bucket], 1)
+}
+
+// PrometheusMetricsRecorder collects validation metrics and exposes them in
+// the Prometheus text exposition format, without depending on the Prometheus
+// client library:
+//
+//   - <namespace>_validations_total: the number of validated documents;
+//   - <namespace>_validation_errors_total: the number of errors by code;
+//   - <namespace>_validation_error_count: a histogram of the number of errors
+//     per document.
+//
+// The recorder implements http.Handler so that it can be served directly.
+type PrometheusMetricsRecorder struct {
+	Namespace string
+
+	mu                sync.Mutex
+	documents         int64
+	errors            map[string]int64
+	errorCountBounds  []int
+	errorCountBuckets []int64
+	errorCountSum     int64
+}
+
+func NewPrometheusMetricsRecorder(namespace string) *PrometheusMetricsRecorder {
+	return &PrometheusMetricsRecorder{
+		Namespace: namespace,
+
+		errors:            make(map[string]int64),
+		errorCountBounds:  append([]int{}, MetricsErrorCountBuckets...),
+		errorCountBuckets: make([]int64, len(MetricsErrorCountBuckets)+1),
+	}
+}
+
+func (r *PrometheusMetricsRecorder) RecordValidation(errs ValidationErrors) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.documents++
+
+	for _, err := range errs {
+		r.errors[err.Code]++
+	}
+
+	bucket := errorCountBucket(r.errorCountBounds, len(errs))
+	r.errorCountBuckets[bucket]++
+	r.errorCountSum += int64(len(errs))
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition
+// format. Error codes are sorted.
+func (r *PrometheusMetricsRecorder) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	name := r.metricName("validations_total")
+	fmt.Fprintf(bw, "# HELP %s Number of validated documents.\n", name)
+	fmt.Fprintf(bw, "# TYPE %s counter\n", name)
+	fmt.Fprintf(bw, "%s %d\n", name, r.documents)
+
+	name = r.metricName("validation_errors_total")
+	fmt.Fprintf(bw, "# HELP %s Number of validation errors by code.\n", name)
+	fmt.Fprintf(bw, "# TYPE %s counter\n", name)
+
+	codes := make([]string, 0, len(r.errors))
+	for code := range r.errors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		fmt.Fprintf(bw, "%s{code=%q} %d\n", name, code, r.errors[code])
+	}
+
+	name = r.metricName("validation_error_count")
+	fmt.Fprintf(bw, "# HELP %s Number of validation errors per document.\n",
+		name)
+	fmt.Fprintf(bw, "# TYPE %s histogram\n", name)
+
+	var count int64
+	for i, n := range r.errorCountBuckets {
+		count += n
+
+		bound := "+Inf"
+		if i < len(r.errorCountBounds) {
+			bound = strconv.Itoa(r.errorCountBounds[i])
+		}
+
+		fmt.Fprintf(bw, "%s_bucket{le=%q} %d\n", name, bound, count)
+	}
+
+	fmt.Fprintf(bw, "%s_sum %d\n", name, r.errorCountSum)
+	fmt.Fprintf(bw, "%s_count %d\n", name, r.documents)
+
+	return bw.Flush()
+}
+
+func (r *PrometheusMetricsRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WritePrometheus(w)
+}
+
+func (r *PrometheusMetricsRecorder) metricName(name string) string {
+	if r.Namespace == "" {
+		return name
+	}
+
+	return r.Namespace + "_" + name
+}
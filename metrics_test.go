@@ -0,0 +1,122 @@
+package ejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetricsRecorder struct {
+	results []ValidationErrors
+}
+
+func (r *testMetricsRecorder) RecordValidation(errs ValidationErrors) {
+	r.results = append(r.results, errs)
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	var recorder testMetricsRecorder
+	SetMetricsRecorder(&recorder)
+	defer SetMetricsRecorder(nil)
+
+	var foo TestFoo
+	assert.NoError(Unmarshal([]byte(`{"String": "abc"}`), &foo))
+	assert.Error(Unmarshal([]byte(`{"String": "a"}`), &foo))
+	assert.Error(Unmarshal([]byte(`{"String": 42}`), &foo))
+	assert.Error(Unmarshal([]byte(`{"String": `), &foo))
+
+	if assert.Equal(3, len(recorder.results)) {
+		assert.Nil(recorder.results[0])
+		if assert.Equal(1, len(recorder.results[1])) {
+			assert.Equal("string_too_short", recorder.results[1][0].Code)
+		}
+		if assert.Equal(1, len(recorder.results[2])) {
+			assert.Equal("invalid_value_type", recorder.results[2][0].Code)
+		}
+	}
+
+	assert.Error(ValidateDeep(&TestFoo{}))
+	if assert.Equal(4, len(recorder.results)) {
+		assert.Equal(1, len(recorder.results[3]))
+	}
+
+	// Internal decoding does not count as the validation of a document
+	d := NewUnionDecoder("type")
+	d.Register("circle", TestCircle{})
+	_, err := d.DecodeArray([]byte(`[{"type": "circle", "radius": 0}]`))
+	assert.Error(err)
+	_, err = ParsePatch([]byte(`[{"op": "remove", "path": "/a"}]`))
+	assert.NoError(err)
+	assert.Equal(4, len(recorder.results))
+
+	SetMetricsRecorder(nil)
+	assert.Error(Validate(&TestFoo{}))
+	assert.Equal(4, len(recorder.results))
+}
+
+func TestExpvarMetricsRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewExpvarMetricsRecorder("ejson_test")
+	assert.Equal(r.Map, expvar.Get("ejson_test"))
+
+	r.RecordValidation(nil)
+	r.RecordValidation(ValidationErrors{
+		&ValidationError{Code: "a"},
+		&ValidationError{Code: "b"},
+		&ValidationError{Code: "a"},
+	})
+
+	var metrics interface{}
+	if assert.NoError(json.Unmarshal([]byte(r.Map.String()), &metrics)) {
+		assert.Equal(map[string]interface{}{
+			"documents":         2.0,
+			"invalid_documents": 1.0,
+			"errors":            map[string]interface{}{"a": 2.0, "b": 1.0},
+			"error_counts":      map[string]interface{}{"0": 1.0, "5": 1.0},
+		}, metrics)
+	}
+}
+
+func TestPrometheusMetricsRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewPrometheusMetricsRecorder("app")
+
+	r.RecordValidation(nil)
+	r.RecordValidation(ValidationErrors{&ValidationError{Code: "b"}})
+	r.RecordValidation(ValidationErrors{
+		&ValidationError{Code: "a"},
+		&ValidationError{Code: "b"},
+	})
+
+	var buf bytes.Buffer
+	if assert.NoError(r.WritePrometheus(&buf)) {
+		assert.Equal(`# HELP app_validations_total Number of validated documents.
+# TYPE app_validations_total counter
+app_validations_total 3
+# HELP app_validation_errors_total Number of validation errors by code.
+# TYPE app_validation_errors_total counter
+app_validation_errors_total{code="a"} 1
+app_validation_errors_total{code="b"} 2
+# HELP app_validation_error_count Number of validation errors per document.
+# TYPE app_validation_error_count histogram
+app_validation_error_count_bucket{le="0"} 1
+app_validation_error_count_bucket{le="1"} 2
+app_validation_error_count_bucket{le="2"} 3
+app_validation_error_count_bucket{le="5"} 3
+app_validation_error_count_bucket{le="10"} 3
+app_validation_error_count_bucket{le="20"} 3
+app_validation_error_count_bucket{le="50"} 3
+app_validation_error_count_bucket{le="100"} 3
+app_validation_error_count_bucket{le="+Inf"} 3
+app_validation_error_count_sum 3
+app_validation_error_count_count 3
+`, buf.String())
+	}
+}
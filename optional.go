@@ -0,0 +1,203 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Optional and Nullable values keep track of the presence of object members.
+// Since UnmarshalJSON is only called for members present in the object, a
+// value which has not been set after decoding was absent.
+//
+// An explicit null is a valid value for Nullable values. Optional values
+// decoded from null are not set, but the null value is recorded so that
+// CheckOptional can reject it.
+//
+// Both types implement IsZero so that unset values can be omitted by encoders
+// supporting it, e.g. encoding/json with the "omitzero" option starting with
+// Go 1.24.
+//
+// Since UnmarshalJSON decodes the inner value on its own, encoding/json
+// cannot locate errors in this value; the value is therefore checked before
+// decoding so that errors are reported with an exact pointer.
+
+type Optional[T any] struct {
+	Value T
+	Set   bool
+
+	null bool
+}
+
+type Nullable[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+type setValue interface {
+	IsSet() bool
+	IsNull() bool
+}
+
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Set: true}
+}
+
+func (o Optional[T]) IsSet() bool {
+	return o.Set
+}
+
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+func (o Optional[T]) IsZero() bool {
+	return !o.Set && !o.null
+}
+
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.Value)
+}
+
+func (o Optional[T]) wrappedType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o Optional[T]) checkJSONValue(value interface{}) *ValidationError {
+	return checkWrappedJSONValue(o.wrappedType(), value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*o = Optional[T]{null: true}
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = NewOptional(value)
+	return nil
+}
+
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{Value: value, Set: true}
+}
+
+func NewNull[T any]() Nullable[T] {
+	return Nullable[T]{Set: true, Null: true}
+}
+
+func (n Nullable[T]) IsSet() bool {
+	return n.Set
+}
+
+func (n Nullable[T]) IsNull() bool {
+	return n.Set && n.Null
+}
+
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Set && !n.Null
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || n.Null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.Value)
+}
+
+func (n Nullable[T]) wrappedType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (n Nullable[T]) checkJSONValue(value interface{}) *ValidationError {
+	return checkWrappedJSONValue(n.wrappedType(), value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*n = NewNull[T]()
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*n = NewNullable(value)
+	return nil
+}
+
+// Check that a generic value can be decoded into a value of the type wrapped
+// by an optional or nullable value. The pointer of the error returned is
+// relative to the value.
+func checkWrappedJSONValue(t reflect.Type, value interface{}) *ValidationError {
+	var errs ValidationErrors
+
+	decodingCheck(t, value, Pointer{}, &errs)
+	if len(errs) == 0 {
+		decodeValue(reflect.New(t).Elem(), value, Pointer{}, &errs)
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// CheckOptional checks that a value is either absent or set to a non-null
+// value.
+func (v *Validator) CheckOptional(token interface{}, value interface{}) bool {
+	if checkSetValue(value).IsNull() {
+		v.AddError(token, "null_value", "value must not be null")
+		return false
+	}
+
+	return true
+}
+
+// CheckSetNotNull checks that a value is present and not null.
+func (v *Validator) CheckSetNotNull(token interface{}, value interface{}) bool {
+	sv := checkSetValue(value)
+
+	if sv.IsNull() {
+		v.AddError(token, "null_value", "value must not be null")
+		return false
+	}
+
+	if !sv.IsSet() {
+		v.AddError(token, "missing_value", "missing value")
+		return false
+	}
+
+	return true
+}
+
+func checkSetValue(value interface{}) setValue {
+	sv, ok := value.(setValue)
+	if !ok {
+		panic(fmt.Sprintf("value %#v (%T) is not an optional or nullable "+
+			"value", value, value))
+	}
+
+	return sv
+}
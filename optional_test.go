@@ -0,0 +1,104 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestPatch struct {
+	Name  Optional[string] `json:"name"`
+	Email Nullable[string] `json:"email"`
+	Age   Nullable[int]    `json:"age"`
+}
+
+func (p *TestPatch) ValidateJSON(v *Validator) {
+	v.CheckOptional("name", p.Name)
+	v.CheckOptional("email", p.Email)
+	v.CheckSetNotNull("age", p.Age)
+}
+
+func TestOptional(t *testing.T) {
+	assert := assert.New(t)
+
+	var patch TestPatch
+	var validationErrs ValidationErrors
+
+	err := Unmarshal([]byte(`{"name": "bob", "age": 42}`), &patch)
+	if assert.NoError(err) {
+		assert.Equal(NewOptional("bob"), patch.Name)
+		assert.False(patch.Email.IsSet())
+		assert.Equal(NewNullable(42), patch.Age)
+	}
+
+	patch = TestPatch{}
+	err = Unmarshal([]byte(`{"name": null, "email": null}`), &patch)
+	if assert.ErrorAs(err, &validationErrs) {
+		assert.False(patch.Name.IsSet())
+		assert.True(patch.Name.IsNull())
+		assert.True(patch.Email.IsNull())
+
+		if assert.Equal(3, len(validationErrs)) {
+			assert.Equal("/name", validationErrs[0].Pointer.String())
+			assert.Equal("null_value", validationErrs[0].Code)
+
+			assert.Equal("/email", validationErrs[1].Pointer.String())
+			assert.Equal("null_value", validationErrs[1].Code)
+
+			assert.Equal("/age", validationErrs[2].Pointer.String())
+			assert.Equal("missing_value", validationErrs[2].Code)
+		}
+	}
+
+	assert.False(patch.Name.IsZero())
+	assert.True(Optional[string]{}.IsZero())
+
+	data, err := json.Marshal(TestPatch{
+		Name:  NewOptional("alice"),
+		Email: NewNull[string](),
+	})
+	if assert.NoError(err) {
+		assert.JSONEq(`{"name": "alice", "email": null, "age": null}`,
+			string(data))
+	}
+}
+
+func TestOptionalErrorPointer(t *testing.T) {
+	assert := assert.New(t)
+
+	type Item struct {
+		Age  Optional[int]      `json:"age"`
+		Tags Nullable[[]string] `json:"tags"`
+	}
+
+	type Document struct {
+		Items []Item `json:"items"`
+	}
+
+	var doc Document
+	var validationErrs ValidationErrors
+
+	err := Unmarshal([]byte(`{"items":[{"age":"x"}]}`), &doc)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/items/0/age", validationErrs[0].Pointer.String())
+			assert.Equal("invalid_value_type", validationErrs[0].Code)
+		}
+	}
+
+	err = Unmarshal([]byte(`{"items":[{}, {"tags":["a", 1]}]}`), &doc)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/items/1/tags/1", validationErrs[0].Pointer.String())
+			assert.Equal("invalid_value_type", validationErrs[0].Code)
+		}
+	}
+
+	doc = Document{}
+	err = Unmarshal([]byte(`{"items":[{"age":42, "tags":null}]}`), &doc)
+	if assert.NoError(err) {
+		assert.Equal([]Item{{Age: NewOptional(42), Tags: NewNull[[]string]()}},
+			doc.Items)
+	}
+}
@@ -0,0 +1,130 @@
+package ejson
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the set of requirements checked by
+// CheckStringPasswordStrength. Zero values disable the associated
+// requirement.
+type PasswordPolicy struct {
+	MinLength int
+
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+
+	// The minimum entropy in bits as estimated by PasswordEntropy.
+	MinEntropy float64
+
+	// Passwords which are always rejected; comparison is case-insensitive.
+	DeniedPasswords []string
+}
+
+// CommonPasswords is a short list of very common passwords which can be used
+// as a deny-list in password policies.
+var CommonPasswords = []string{
+	"000000", "111111", "123123", "123321", "1234", "12345", "123456",
+	"1234567", "12345678", "123456789", "1234567890", "654321", "666666",
+	"abc123", "admin", "dragon", "football", "iloveyou", "letmein", "master",
+	"monkey", "password", "password1", "qwerty", "qwerty123", "qwertyuiop",
+	"sunshine", "welcome",
+}
+
+// PasswordEntropy returns a rough estimation of the entropy of a password in
+// bits, computed as if each character had been chosen randomly in the
+// character classes used in the password.
+func PasswordEntropy(s string) float64 {
+	var lower, upper, digit, symbol, other bool
+
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		case c < utf8.RuneSelf && unicode.IsPrint(c):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	var poolSize int
+	if lower {
+		poolSize += 26
+	}
+	if upper {
+		poolSize += 26
+	}
+	if digit {
+		poolSize += 10
+	}
+	if symbol {
+		poolSize += 33
+	}
+	if other {
+		poolSize += 100
+	}
+
+	if poolSize == 0 {
+		return 0.0
+	}
+
+	return float64(utf8.RuneCountInString(s)) * math.Log2(float64(poolSize))
+}
+
+// CheckStringPasswordStrength checks that a string satisfies a password
+// policy. An error is added for each requirement which is not satisfied.
+func (v *Validator) CheckStringPasswordStrength(token interface{}, s string, policy PasswordPolicy) bool {
+	nbErrors := len(v.Errors)
+
+	if policy.MinLength > 0 && utf8.RuneCountInString(s) < policy.MinLength {
+		v.AddError(token, "password_too_short",
+			"password must contain at least %d characters", policy.MinLength)
+	}
+
+	if policy.RequireLowercase && strings.IndexFunc(s, unicode.IsLower) < 0 {
+		v.AddError(token, "password_missing_lowercase",
+			"password must contain at least one lowercase letter")
+	}
+
+	if policy.RequireUppercase && strings.IndexFunc(s, unicode.IsUpper) < 0 {
+		v.AddError(token, "password_missing_uppercase",
+			"password must contain at least one uppercase letter")
+	}
+
+	if policy.RequireDigit && strings.IndexFunc(s, unicode.IsDigit) < 0 {
+		v.AddError(token, "password_missing_digit",
+			"password must contain at least one digit")
+	}
+
+	if policy.RequireSymbol && strings.IndexFunc(s, isPasswordSymbol) < 0 {
+		v.AddError(token, "password_missing_symbol",
+			"password must contain at least one symbol")
+	}
+
+	if policy.MinEntropy > 0.0 && PasswordEntropy(s) < policy.MinEntropy {
+		v.AddError(token, "password_too_weak", "password is too weak")
+	}
+
+	for _, password := range policy.DeniedPasswords {
+		if strings.EqualFold(s, password) {
+			v.AddError(token, "password_too_common", "password is too common")
+			break
+		}
+	}
+
+	return len(v.Errors) == nbErrors
+}
+
+func isPasswordSymbol(c rune) bool {
+	return !unicode.IsLetter(c) && !unicode.IsDigit(c) && !unicode.IsSpace(c) &&
+		unicode.IsPrint(c)
+}
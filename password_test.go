@@ -0,0 +1,60 @@
+package ejson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordEntropy(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0.0, PasswordEntropy(""))
+	assert.InDelta(4.0*math.Log2(26), PasswordEntropy("abcd"), 1e-9)
+	assert.InDelta(4.0*math.Log2(62), PasswordEntropy("aB1c"), 1e-9)
+	assert.InDelta(4.0*math.Log2(95), PasswordEntropy("aB1!"), 1e-9)
+	assert.InDelta(2.0*math.Log2(126), PasswordEntropy("aé"), 1e-9)
+}
+
+func TestCheckStringPasswordStrength(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := PasswordPolicy{
+		MinLength:        8,
+		RequireLowercase: true,
+		RequireUppercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		MinEntropy:       40.0,
+		DeniedPasswords:  CommonPasswords,
+	}
+
+	assertErrors := func(expectedCodes []string, s string) {
+		t.Helper()
+
+		v := NewValidator()
+		valid := v.CheckStringPasswordStrength("password", s, policy)
+
+		var codes []string
+		for _, err := range v.Errors {
+			assert.Equal("/password", err.Pointer.String())
+			codes = append(codes, err.Code)
+		}
+
+		assert.Equal(len(expectedCodes) == 0, valid, s)
+		assert.Equal(expectedCodes, codes, s)
+	}
+
+	assertErrors(nil, "Corr3ct-Horse")
+	assertErrors(nil, "éÉ1 été!")
+	assertErrors([]string{"password_too_short", "password_missing_uppercase",
+		"password_missing_digit", "password_missing_symbol",
+		"password_too_weak"}, "abc")
+	assertErrors([]string{"password_missing_symbol", "password_too_common"},
+		"Password1")
+	assertErrors([]string{"password_missing_lowercase"}, "ABCDEFGH1!")
+
+	v := NewValidator()
+	assert.True(v.CheckStringPasswordStrength("password", "", PasswordPolicy{}))
+}
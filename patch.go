@@ -0,0 +1,393 @@
+package ejson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Patch is a JSON patch document (RFC 6902).
+type Patch []PatchOperation
+
+type PatchOp string
+
+const (
+	PatchOpAdd     PatchOp = "add"
+	PatchOpRemove  PatchOp = "remove"
+	PatchOpReplace PatchOp = "replace"
+	PatchOpMove    PatchOp = "move"
+	PatchOpCopy    PatchOp = "copy"
+	PatchOpTest    PatchOp = "test"
+)
+
+var PatchOpValues = []PatchOp{
+	PatchOpAdd,
+	PatchOpRemove,
+	PatchOpReplace,
+	PatchOpMove,
+	PatchOpCopy,
+	PatchOpTest,
+}
+
+// From is only used for move and copy operations; Value is only used for
+// add, replace and test operations.
+type PatchOperation struct {
+	Op    PatchOp
+	Path  Pointer
+	From  Pointer
+	Value interface{}
+}
+
+var (
+	ErrPatchTestFailed   = errors.New("test failed")
+	ErrPatchMoveIntoSelf = errors.New("cannot move a value into one of " +
+		"its children")
+)
+
+type PatchError struct {
+	Index int
+	Op    PatchOperation
+	Err   error
+}
+
+func (err *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %q): %v",
+		err.Index, err.Op.Op, err.Op.Path.String(), err.Err)
+}
+
+func (err *PatchError) Unwrap() error {
+	return err.Err
+}
+
+// Pointers are decoded as strings so that invalid pointers are reported as
+// validation errors.
+type patchOperationJSON struct {
+	Op    PatchOp         `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type patchJSON []patchOperationJSON
+
+func (op *patchOperationJSON) ValidateJSON(v *Validator) {
+	if v.CheckStringValue("op", op.Op, PatchOpValues) {
+		switch op.Op {
+		case PatchOpMove, PatchOpCopy:
+			if v.Check("from", op.From != nil, "missing_value",
+				"missing value") {
+				checkPatchPointer(v, "from", *op.From)
+			}
+
+		case PatchOpAdd, PatchOpReplace, PatchOpTest:
+			v.Check("value", len(op.Value) > 0, "missing_value",
+				"missing value")
+		}
+	}
+
+	if v.Check("path", op.Path != nil, "missing_value", "missing value") {
+		checkPatchPointer(v, "path", *op.Path)
+	}
+}
+
+func checkPatchPointer(v *Validator, token string, s string) bool {
+	var p Pointer
+	if err := p.Parse(s); err != nil {
+		v.AddError(token, "invalid_pointer", "invalid json pointer: %v", err)
+		return false
+	}
+
+	return true
+}
+
+func (p *patchJSON) ValidateJSON(v *Validator) {
+	for i := range *p {
+		v.WithChild(i, func() {
+			(*p)[i].ValidateJSON(v)
+		})
+	}
+}
+
+func (op *patchOperationJSON) operation() (PatchOperation, error) {
+	op2 := PatchOperation{Op: op.Op}
+
+	if op.Path != nil {
+		if err := op2.Path.Parse(*op.Path); err != nil {
+			return op2, err
+		}
+	}
+
+	if op.From != nil {
+		if err := op2.From.Parse(*op.From); err != nil {
+			return op2, err
+		}
+	}
+
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &op2.Value); err != nil {
+			return op2, err
+		}
+	}
+
+	return op2, nil
+}
+
+// ParsePatch decodes and validates a JSON patch document. Validation errors
+// are returned as ValidationErrors.
+func ParsePatch(data []byte) (Patch, error) {
+	var ops patchJSON
+	if err := unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+
+	patch := make(Patch, len(ops))
+
+	for i, op := range ops {
+		op2, err := op.operation()
+		if err != nil {
+			return nil, err
+		}
+
+		patch[i] = op2
+	}
+
+	return patch, nil
+}
+
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	path := op.Path.String()
+	op2 := patchOperationJSON{Op: op.Op, Path: &path}
+
+	switch op.Op {
+	case PatchOpMove, PatchOpCopy:
+		from := op.From.String()
+		op2.From = &from
+
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		value, err := json.Marshal(op.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		op2.Value = value
+	}
+
+	return json.Marshal(op2)
+}
+
+func (op *PatchOperation) UnmarshalJSON(data []byte) error {
+	var op2 patchOperationJSON
+	if err := json.Unmarshal(data, &op2); err != nil {
+		return err
+	}
+
+	if err := validate(&op2); err != nil {
+		return err
+	}
+
+	op3, err := op2.operation()
+	if err != nil {
+		return err
+	}
+
+	*op = op3
+	return nil
+}
+
+// Apply applies all operations of the patch to a copy of a generic JSON value
+// and returns the result. If an operation fails, a *PatchError is returned and
+// the original value is left unchanged.
+func (p Patch) Apply(value interface{}) (interface{}, error) {
+	value = Clone(value)
+
+	for i, op := range p {
+		var err error
+
+		value, err = op.apply(value)
+		if err != nil {
+			return nil, &PatchError{Index: i, Op: op, Err: err}
+		}
+	}
+
+	return value, nil
+}
+
+func (op *PatchOperation) apply(value interface{}) (interface{}, error) {
+	switch op.Op {
+	case PatchOpAdd:
+		return op.Path.Insert(value, Clone(op.Value))
+
+	case PatchOpRemove:
+		return op.Path.Delete(value)
+
+	case PatchOpReplace:
+		if _, err := op.Path.Resolve(value); err != nil {
+			return nil, err
+		}
+
+		return op.Path.Set(value, Clone(op.Value))
+
+	case PatchOpMove:
+		if op.Path.hasPrefix(op.From) && len(op.Path) > len(op.From) {
+			return nil, ErrPatchMoveIntoSelf
+		}
+
+		v, err := op.From.Resolve(value)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err = op.From.Delete(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return op.Path.Insert(value, v)
+
+	case PatchOpCopy:
+		v, err := op.From.Resolve(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return op.Path.Insert(value, Clone(v))
+
+	case PatchOpTest:
+		v, err := op.Path.Resolve(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !Equal(v, op.Value) {
+			return nil, ErrPatchTestFailed
+		}
+
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// Diff returns a patch which transforms the first value into the second one
+// when applied to it. Objects are compared member by member. Arrays are
+// compared using the longest common subsequence of their elements, so that
+// the patch contains as few add and remove operations as possible; move and
+// copy operations are never generated.
+func Diff(a, b interface{}) Patch {
+	patch := Patch{}
+	diff(Pointer{}, a, b, &patch)
+	return patch
+}
+
+func diff(p Pointer, a, b interface{}, patch *Patch) {
+	if Equal(a, b) {
+		return
+	}
+
+	switch {
+	case IsObject(a) && IsObject(b):
+		obj1 := AsObject(a)
+		obj2 := AsObject(b)
+
+		for _, name := range sortedMemberNames(obj1) {
+			if _, found := obj2[name]; !found {
+				*patch = append(*patch, PatchOperation{
+					Op:   PatchOpRemove,
+					Path: p.Child(name),
+				})
+			}
+		}
+
+		for _, name := range sortedMemberNames(obj2) {
+			if value1, found := obj1[name]; found {
+				diff(p.Child(name), value1, obj2[name], patch)
+			} else {
+				*patch = append(*patch, PatchOperation{
+					Op:    PatchOpAdd,
+					Path:  p.Child(name),
+					Value: Clone(obj2[name]),
+				})
+			}
+		}
+
+	case IsArray(a) && IsArray(b):
+		diffArrays(p, AsArray(a), AsArray(b), patch)
+
+	default:
+		*patch = append(*patch, PatchOperation{
+			Op:    PatchOpReplace,
+			Path:  p.Child(),
+			Value: Clone(b),
+		})
+	}
+}
+
+func diffArrays(p Pointer, a1, a2 []interface{}, patch *Patch) {
+	// Common prefixes and suffixes do not have to go through the LCS table
+	start := 0
+	for start < len(a1) && start < len(a2) && Equal(a1[start], a2[start]) {
+		start++
+	}
+
+	end1, end2 := len(a1), len(a2)
+	for end1 > start && end2 > start && Equal(a1[end1-1], a2[end2-1]) {
+		end1--
+		end2--
+	}
+
+	a1 = a1[start:end1]
+	a2 = a2[start:end2]
+
+	// lcs[i][j] is the length of the longest common subsequence of a1[i:]
+	// and a2[j:].
+	n, m := len(a1), len(a2)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if Equal(a1[i], a2[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Once a2[:j] has been produced, the next element of the array being
+	// patched is at index start+j.
+	i, j := 0, 0
+
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && lcs[i][j] == lcs[i+1][j+1]+1 &&
+			Equal(a1[i], a2[j]):
+			i++
+			j++
+
+		case i < n && j < m && lcs[i][j] == lcs[i+1][j+1]:
+			// Neither element is part of the common subsequence
+			diff(p.Child(start+j), a1[i], a2[j], patch)
+			i++
+			j++
+
+		case j < m && lcs[i][j] == lcs[i][j+1]:
+			*patch = append(*patch, PatchOperation{
+				Op:    PatchOpAdd,
+				Path:  p.Child(start + j),
+				Value: Clone(a2[j]),
+			})
+			j++
+
+		default:
+			*patch = append(*patch, PatchOperation{
+				Op:   PatchOpRemove,
+				Path: p.Child(start + j),
+			})
+			i++
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchApply(t *testing.T) {
+	assert := assert.New(t)
+
+	decode := func(s string) interface{} {
+		t.Helper()
+
+		var value interface{}
+		if err := Unmarshal([]byte(s), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	assertApply := func(expected, value, patchString string) {
+		t.Helper()
+
+		patch, err := ParsePatch([]byte(patchString))
+		if !assert.NoError(err, patchString) {
+			return
+		}
+
+		originalValue := decode(value)
+		value2, err := patch.Apply(originalValue)
+		if assert.NoError(err, patchString) {
+			assert.Equal(decode(expected), value2, patchString)
+			assert.Equal(decode(value), originalValue, patchString)
+		}
+	}
+
+	assertApplyError := func(expectedErr error, expectedIndex int, value, patchString string) {
+		t.Helper()
+
+		patch, err := ParsePatch([]byte(patchString))
+		if !assert.NoError(err, patchString) {
+			return
+		}
+
+		_, err = patch.Apply(decode(value))
+
+		var patchErr *PatchError
+		if assert.ErrorAs(err, &patchErr, patchString) {
+			assert.Equal(expectedIndex, patchErr.Index, patchString)
+			assert.ErrorIs(err, expectedErr, patchString)
+		}
+	}
+
+	// Examples from RFC 6902 Appendix A
+	assertApply(`{"baz": "qux", "foo": "bar"}`, `{"foo": "bar"}`,
+		`[{"op": "add", "path": "/baz", "value": "qux"}]`)
+	assertApply(`{"foo": ["bar", "qux", "baz"]}`, `{"foo": ["bar", "baz"]}`,
+		`[{"op": "add", "path": "/foo/1", "value": "qux"}]`)
+	assertApply(`{"foo": "bar"}`, `{"baz": "qux", "foo": "bar"}`,
+		`[{"op": "remove", "path": "/baz"}]`)
+	assertApply(`{"foo": ["bar", "baz"]}`, `{"foo": ["bar", "qux", "baz"]}`,
+		`[{"op": "remove", "path": "/foo/1"}]`)
+	assertApply(`{"baz": "boo", "foo": "bar"}`, `{"baz": "qux", "foo": "bar"}`,
+		`[{"op": "replace", "path": "/baz", "value": "boo"}]`)
+	assertApply(`{"foo": {"bar": "baz"}, "qux": {"corge": "grault",
+"thud": "fred"}}`, `{"foo": {"bar": "baz", "waldo": "fred"},
+"qux": {"corge": "grault"}}`,
+		`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`)
+	assertApply(`{"foo": ["all", "cows", "eat", "grass"]}`,
+		`{"foo": ["all", "grass", "cows", "eat"]}`,
+		`[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`)
+	assertApply(`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		`[{"op": "test", "path": "/baz", "value": "qux"},
+{"op": "test", "path": "/foo/1", "value": 2}]`)
+	assertApply(`{"foo": "bar", "child": {"grandchild": {}}}`,
+		`{"foo": "bar"}`,
+		`[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`)
+	assertApply(`{"foo": ["bar", ["abc", "def"]]}`, `{"foo": ["bar"]}`,
+		`[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`)
+	assertApply(`{"foo": null, "bar": null}`, `{"foo": null}`,
+		`[{"op": "copy", "from": "/foo", "path": "/bar"},
+{"op": "test", "path": "/bar", "value": null}]`)
+	assertApply(`[1]`, `{"a": 1}`,
+		`[{"op": "replace", "path": "", "value": [1]}]`)
+
+	assertApplyError(ErrPatchTestFailed, 1, `{"baz": "qux"}`,
+		`[{"op": "test", "path": "/baz", "value": "qux"},
+{"op": "test", "path": "/baz", "value": "bar"}]`)
+	assertApplyError(ErrMemberNotFound, 0, `{"foo": "bar"}`,
+		`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`)
+	assertApplyError(ErrMemberNotFound, 0, `{"foo": "bar"}`,
+		`[{"op": "replace", "path": "/baz", "value": "qux"}]`)
+	assertApplyError(ErrArrayIndexOutOfRange, 0, `[1, 2]`,
+		`[{"op": "remove", "path": "/2"}]`)
+	assertApplyError(ErrPatchMoveIntoSelf, 0, `{"a": {"b": 1}}`,
+		`[{"op": "move", "from": "/a", "path": "/a/c"}]`)
+}
+
+func TestParsePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	patch, err := ParsePatch([]byte(`[
+  {"op": "add", "path": "/a/-", "value": null},
+  {"op": "copy", "from": "", "path": "/b"}
+]`))
+	if assert.NoError(err) {
+		assert.Equal(Patch{
+			{Op: PatchOpAdd, Path: NewPointer("a", "-")},
+			{Op: PatchOpCopy, Path: NewPointer("b"), From: NewPointer()},
+		}, patch)
+
+		data, err := json.Marshal(patch)
+		if assert.NoError(err) {
+			assert.JSONEq(`[
+  {"op": "add", "path": "/a/-", "value": null},
+  {"op": "copy", "from": "", "path": "/b"}
+]`, string(data))
+		}
+	}
+
+	_, err = ParsePatch([]byte(`[{"op": "foo", "path": "/a"},
+{"op": "move", "path": "/a"}, {"op": "test"}]`))
+
+	var validationErrs ValidationErrors
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+		}
+
+		assert.Equal([]string{"/0/op", "/1/from", "/2/value", "/2/path"},
+			pointers)
+	}
+
+	_, err = ParsePatch([]byte(`[{"op": "add", "path": "/a", "value": 1},
+{"op": "add", "path": "a", "value": 2}, {"op": "copy", "from": "b",
+"path": "/c"}]`))
+
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+			assert.Equal("invalid_pointer", err.Code)
+		}
+
+		assert.Equal([]string{"/1/path", "/2/from"}, pointers)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	decode := func(s string) interface{} {
+		t.Helper()
+
+		var value interface{}
+		if err := Unmarshal([]byte(s), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	assertDiff := func(expected, a, b string) {
+		t.Helper()
+
+		value1 := decode(a)
+		value2 := decode(b)
+
+		patch := Diff(value1, value2)
+
+		data, err := json.Marshal(patch)
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.JSONEq(expected, string(data), b)
+
+		value3, err := patch.Apply(value1)
+		if assert.NoError(err, b) {
+			assert.Equal(value2, value3, b)
+		}
+	}
+
+	assertDiff(`[]`, `{"a": [1, {"b": 2}]}`, `{"a": [1, {"b": 2}]}`)
+	assertDiff(`[{"op": "replace", "path": "", "value": [1]}]`, `{}`, `[1]`)
+	assertDiff(`[
+  {"op": "remove", "path": "/b"},
+  {"op": "replace", "path": "/a/x", "value": 2},
+  {"op": "add", "path": "/c", "value": {"d": null}}
+]`, `{"a": {"x": 1, "y": true}, "b": "x"}`,
+		`{"a": {"x": 2, "y": true}, "c": {"d": null}}`)
+	assertDiff(`[
+  {"op": "replace", "path": "/0", "value": 0},
+  {"op": "remove", "path": "/2"},
+  {"op": "remove", "path": "/2"}
+]`, `[1, 2, 3, 4]`, `[0, 2]`)
+	assertDiff(`[
+  {"op": "add", "path": "/1", "value": 2},
+  {"op": "add", "path": "/2", "value": [3]}
+]`, `[1]`, `[1, 2, [3]]`)
+	assertDiff(`[{"op": "add", "path": "/0", "value": 0}]`,
+		`[1, 2, 3]`, `[0, 1, 2, 3]`)
+	assertDiff(`[
+  {"op": "remove", "path": "/1"},
+  {"op": "remove", "path": "/2"}
+]`, `[1, 2, 3, 4, 5]`, `[1, 3, 5]`)
+	assertDiff(`[
+  {"op": "remove", "path": "/0"},
+  {"op": "add", "path": "/2", "value": 4}
+]`, `[1, 2, 3]`, `[2, 3, 4]`)
+	assertDiff(`[
+  {"op": "replace", "path": "/1/b", "value": 3},
+  {"op": "add", "path": "/2", "value": {"c": 4}}
+]`, `[{"a": 1}, {"b": 2}, 5]`, `[{"a": 1}, {"b": 3}, {"c": 4}, 5]`)
+}
@@ -0,0 +1,88 @@
+package ejson
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A pointer pattern is a JSON pointer whose tokens can be wildcards: "*"
+// matches any single token, and "**", which can only be used as the last
+// token, matches any sequence of tokens, including an empty one. There is no
+// way to match members whose name is "*" or "**" exactly.
+type PointerPattern []string
+
+var ErrInvalidPointerPatternWildcard = errors.New("\"**\" wildcard must be " +
+	"the last token")
+
+func (pp *PointerPattern) Parse(s string) error {
+	var p Pointer
+	if err := p.Parse(s); err != nil {
+		return err
+	}
+
+	for i, token := range p {
+		if token == "**" && i < len(p)-1 {
+			return ErrInvalidPointerPatternWildcard
+		}
+	}
+
+	*pp = PointerPattern(p)
+	return nil
+}
+
+func (pp *PointerPattern) MustParse(s string) {
+	if err := pp.Parse(s); err != nil {
+		panic(fmt.Sprintf("invalid json pointer pattern %q: %v", s, err))
+	}
+}
+
+func (pp PointerPattern) String() string {
+	return Pointer(pp).String()
+}
+
+func (pp PointerPattern) MarshalJSON() ([]byte, error) {
+	return Pointer(pp).MarshalJSON()
+}
+
+func (pp *PointerPattern) UnmarshalJSON(data []byte) error {
+	var p Pointer
+	if err := p.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	return pp.Parse(p.String())
+}
+
+func (pp PointerPattern) Match(p Pointer) bool {
+	for i, token := range pp {
+		if token == "**" {
+			return true
+		}
+
+		if i >= len(p) {
+			return false
+		}
+
+		if token != "*" && token != p[i] {
+			return false
+		}
+	}
+
+	return len(pp) == len(p)
+}
+
+// Filter returns errors whose pointer matches at least one of the patterns.
+func (errs ValidationErrors) Filter(patterns ...PointerPattern) ValidationErrors {
+	var errs2 ValidationErrors
+
+	for _, err := range errs {
+		for _, pattern := range patterns {
+			if pattern.Match(err.Pointer) {
+				errs2 = append(errs2, err)
+				break
+			}
+		}
+	}
+
+	return errs2
+}
@@ -0,0 +1,63 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointerPatternMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	assertMatch := func(expected bool, pattern, pointer string) {
+		t.Helper()
+
+		var pp PointerPattern
+		pp.MustParse(pattern)
+
+		var p Pointer
+		p.MustParse(pointer)
+
+		assert.Equal(expected, pp.Match(p), "%q %q", pattern, pointer)
+	}
+
+	assertMatch(true, "", "")
+	assertMatch(false, "", "/a")
+	assertMatch(true, "/a/b", "/a/b")
+	assertMatch(false, "/a/b", "/a/c")
+	assertMatch(false, "/a/b", "/a")
+	assertMatch(false, "/a", "/a/b")
+	assertMatch(true, "/items/*/price", "/items/0/price")
+	assertMatch(true, "/items/*/price", "/items/foo/price")
+	assertMatch(false, "/items/*/price", "/items/price")
+	assertMatch(false, "/items/*/price", "/items/0/1/price")
+	assertMatch(true, "/items/**", "/items")
+	assertMatch(true, "/items/**", "/items/0/price")
+	assertMatch(false, "/items/**", "/item")
+	assertMatch(true, "/**", "")
+	assertMatch(true, "/*/**", "/a/b/c")
+
+	var pp PointerPattern
+	assert.Error(pp.Parse("/a/**/b"))
+}
+
+func TestValidationErrorsFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	var pp1, pp2 PointerPattern
+	pp1.MustParse("/items/*/price")
+	pp2.MustParse("/user/**")
+
+	errs := ValidationErrors{
+		{Pointer: NewPointer("items", 0, "price")},
+		{Pointer: NewPointer("items", 0, "name")},
+		{Pointer: NewPointer("user")},
+		{Pointer: NewPointer("items", 1, "price")},
+		{Pointer: NewPointer("user", "email")},
+	}
+
+	assert.Equal(ValidationErrors{errs[0], errs[2], errs[3], errs[4]},
+		errs.Filter(pp1, pp2))
+	assert.Equal(ValidationErrors{errs[2], errs[4]}, errs.Filter(pp2))
+	assert.Nil(errs.Filter())
+}
@@ -11,8 +11,38 @@ import (
 
 type Pointer []string
 
+// Pointers store tokens as strings; the Token type provides methods to
+// interpret them.
+type Token string
+
+// The "-" token references the (nonexistent) element after the last element
+// of an array. It is used to append values to arrays.
+const AppendToken = "-"
+
 var ErrInvalidPointerFormat = errors.New("invalid format")
 
+var (
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInvalidArrayIndex    = errors.New("invalid array index")
+	ErrArrayIndexOutOfRange = errors.New("array index out of range")
+	ErrNotArrayOrObject     = errors.New("value is neither an array nor " +
+		"an object")
+	ErrEmptyPointer = errors.New("empty pointer")
+)
+
+type PointerError struct {
+	Pointer Pointer
+	Err     error
+}
+
+func (err *PointerError) Error() string {
+	return fmt.Sprintf("json pointer %q: %v", err.Pointer.String(), err.Err)
+}
+
+func (err *PointerError) Unwrap() error {
+	return err.Err
+}
+
 var (
 	tokenEncoder *strings.Replacer
 	tokenDecoder *strings.Replacer
@@ -41,6 +71,10 @@ func (p *Pointer) Parse(s string) error {
 
 	tokens := make([]string, len(parts))
 	for i, part := range parts {
+		if !isValidEncodedToken(part) {
+			return ErrInvalidPointerFormat
+		}
+
 		tokens[i] = decodeToken(part)
 	}
 
@@ -99,25 +133,50 @@ func (p Pointer) Child(tokens ...interface{}) Pointer {
 	p2 := append(Pointer{}, p...)
 
 	for _, token := range tokens {
-		switch v := token.(type) {
-		case string:
-			p2 = append(p2, v)
+		p2 = p2.appendToken(token)
+	}
 
-		case int:
-			p2 = append(p2, strconv.Itoa(v))
+	return p2
+}
 
-		case Pointer:
-			p2 = append(p2, v...)
+// Append a token to the pointer, possibly reusing the underlying array of
+// the slice.
+func (p Pointer) appendToken(token interface{}) Pointer {
+	switch v := token.(type) {
+	case string:
+		p = append(p, v)
 
-		case nil:
+	case Token:
+		p = append(p, string(v))
 
-		default:
-			panic(fmt.Sprintf("invalid json pointer token %#v (%T)",
-				token, token))
+	case int:
+		p = append(p, strconv.Itoa(v))
+
+	case Pointer:
+		p = append(p, v...)
+
+	case nil:
+
+	default:
+		panic(fmt.Sprintf("invalid json pointer token %#v (%T)",
+			token, token))
+	}
+
+	return p
+}
+
+func (p Pointer) hasPrefix(prefix Pointer) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+
+	for i, token := range prefix {
+		if p[i] != token {
+			return false
 		}
 	}
 
-	return p2
+	return true
 }
 
 func (p Pointer) Find(value interface{}) interface{} {
@@ -126,15 +185,11 @@ func (p Pointer) Find(value interface{}) interface{} {
 	for _, token := range p {
 		switch tv := v.(type) {
 		case []interface{}:
-			i, err := strconv.ParseInt(token, 10, 64)
+			i, err := parseArrayIndex(token, len(tv)-1)
 			if err != nil {
 				return nil
 			}
 
-			if i < 0 || i >= int64(len(tv)) {
-				return nil
-			}
-
 			v = tv[i]
 
 		case map[string]interface{}:
@@ -153,6 +208,51 @@ func (p Pointer) Find(value interface{}) interface{} {
 	return v
 }
 
+func (t Token) IsAppend() bool {
+	return t == AppendToken
+}
+
+// IsArrayIndex returns true if the token is a valid array index as defined
+// in RFC 6901, i.e. a non-negative integer without any leading zero.
+func (t Token) IsArrayIndex() bool {
+	if len(t) == 0 || (t[0] == '0' && len(t) > 1) {
+		return false
+	}
+
+	for i := 0; i < len(t); i++ {
+		if t[i] < '0' || t[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t Token) ArrayIndex() (int, bool) {
+	if !t.IsArrayIndex() {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(string(t))
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func isValidEncodedToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '~' {
+			if i == len(s)-1 || (s[i+1] != '0' && s[i+1] != '1') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func encodeToken(s string) string {
 	return tokenEncoder.Replace(s)
 }
@@ -160,3 +260,217 @@ func encodeToken(s string) string {
 func decodeToken(s string) string {
 	return tokenDecoder.Replace(s)
 }
+
+func (p Pointer) Resolve(value interface{}) (interface{}, error) {
+	v := value
+
+	for i, token := range p {
+		child, err := resolveToken(v, token)
+		if err != nil {
+			return nil, &PointerError{Pointer: p[:i+1], Err: err}
+		}
+
+		v = child
+	}
+
+	return v, nil
+}
+
+// Set replaces the value referenced by the pointer, creating the object
+// member if it does not exist or appending the value if the pointer ends with
+// the "-" token. It returns the root value, which is different
+// from the original value if the pointer is empty.
+func (p Pointer) Set(value, v interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+
+	return p.update(value, 0,
+		func(parent interface{}, token string) (interface{}, error) {
+			switch pv := parent.(type) {
+			case []interface{}:
+				if Token(token).IsAppend() {
+					return appendArrayValue(pv, v), nil
+				}
+
+				i, err := parseArrayIndex(token, len(pv)-1)
+				if err != nil {
+					return nil, err
+				}
+
+				pv[i] = v
+				return pv, nil
+
+			case map[string]interface{}:
+				pv[token] = v
+				return pv, nil
+
+			default:
+				return nil, ErrNotArrayOrObject
+			}
+		})
+}
+
+// Insert adds a value at the location referenced by the pointer. For arrays,
+// the value is inserted before the element at the index referenced by the
+// pointer; the index can be equal to the length of the array or be the "-"
+// token to append the value. For objects, the member is created or replaced.
+func (p Pointer) Insert(value, v interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+
+	return p.update(value, 0,
+		func(parent interface{}, token string) (interface{}, error) {
+			switch pv := parent.(type) {
+			case []interface{}:
+				if Token(token).IsAppend() {
+					return appendArrayValue(pv, v), nil
+				}
+
+				i, err := parseArrayIndex(token, len(pv))
+				if err != nil {
+					return nil, err
+				}
+
+				array := make([]interface{}, 0, len(pv)+1)
+				array = append(array, pv[:i]...)
+				array = append(array, v)
+				array = append(array, pv[i:]...)
+
+				return array, nil
+
+			case map[string]interface{}:
+				pv[token] = v
+				return pv, nil
+
+			default:
+				return nil, ErrNotArrayOrObject
+			}
+		})
+}
+
+// Delete removes the value referenced by the pointer. Following elements of
+// arrays are shifted.
+//
+// As for Set and Insert, objects and array elements are modified in place,
+// but arrays whose length changes are copied so that the backing array of
+// the original slice is left untouched.
+func (p Pointer) Delete(value interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return nil, &PointerError{Pointer: p, Err: ErrEmptyPointer}
+	}
+
+	return p.update(value, 0,
+		func(parent interface{}, token string) (interface{}, error) {
+			switch pv := parent.(type) {
+			case []interface{}:
+				i, err := parseArrayIndex(token, len(pv)-1)
+				if err != nil {
+					return nil, err
+				}
+
+				array := make([]interface{}, 0, len(pv)-1)
+				array = append(array, pv[:i]...)
+				array = append(array, pv[i+1:]...)
+
+				return array, nil
+
+			case map[string]interface{}:
+				if _, found := pv[token]; !found {
+					return nil, ErrMemberNotFound
+				}
+
+				delete(pv, token)
+				return pv, nil
+
+			default:
+				return nil, ErrNotArrayOrObject
+			}
+		})
+}
+
+// Update the parent of the value referenced by the pointer with a function
+// and store the resulting value in the parent of the parent, recursively.
+// Arrays have to be replaced in their own parent since their length can
+// change.
+func (p Pointer) update(value interface{}, i int, fn func(interface{}, string) (interface{}, error)) (interface{}, error) {
+	if i == len(p)-1 {
+		newValue, err := fn(value, p[i])
+		if err != nil {
+			return nil, &PointerError{Pointer: p, Err: err}
+		}
+
+		return newValue, nil
+	}
+
+	child, err := resolveToken(value, p[i])
+	if err != nil {
+		return nil, &PointerError{Pointer: p[:i+1], Err: err}
+	}
+
+	newChild, err := p.update(child, i+1, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		idx, _ := parseArrayIndex(p[i], len(v)-1)
+		v[idx] = newChild
+
+	case map[string]interface{}:
+		v[p[i]] = newChild
+	}
+
+	return value, nil
+}
+
+// Append a value to a copy of an array so that the backing array of the
+// original slice is not modified.
+func appendArrayValue(array []interface{}, v interface{}) []interface{} {
+	newArray := make([]interface{}, len(array), len(array)+1)
+	copy(newArray, array)
+
+	return append(newArray, v)
+}
+
+func resolveToken(value interface{}, token string) (interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		i, err := parseArrayIndex(token, len(v)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		return v[i], nil
+
+	case map[string]interface{}:
+		child, found := v[token]
+		if !found {
+			return nil, ErrMemberNotFound
+		}
+
+		return child, nil
+
+	default:
+		return nil, ErrNotArrayOrObject
+	}
+}
+
+func parseArrayIndex(token string, max int) (int, error) {
+	if Token(token).IsAppend() {
+		return 0, ErrArrayIndexOutOfRange
+	}
+
+	i, ok := Token(token).ArrayIndex()
+	if !ok {
+		return 0, ErrInvalidArrayIndex
+	}
+
+	if i > max {
+		return 0, ErrArrayIndexOutOfRange
+	}
+
+	return i, nil
+}
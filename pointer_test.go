@@ -35,6 +35,11 @@ func TestPointerParse(t *testing.T) {
 	assertParse(Pointer{"xy", "", "z", "", ""}, "/xy//z//")
 	assertParse(Pointer{"foo/bar", "~hello"}, "/foo~1bar/~0hello")
 	assertParse(Pointer{"~1", "/0"}, "/~01/~10")
+
+	var p Pointer
+	assert.ErrorIs(p.Parse("foo"), ErrInvalidPointerFormat)
+	assert.ErrorIs(p.Parse("/a~2b"), ErrInvalidPointerFormat)
+	assert.ErrorIs(p.Parse("/a~"), ErrInvalidPointerFormat)
 }
 
 func TestPointerString(t *testing.T) {
@@ -151,3 +156,187 @@ func TestPointerFind(t *testing.T) {
 	assert.Equal(nil,
 		NewPointer("c", "1", "x", "2").Find(obj))
 }
+
+func TestPointerResolve(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"a": [1, {"b": 2}], "c": null}`), &value)
+
+	assertResolve := func(expected interface{}, s string) {
+		t.Helper()
+
+		var p Pointer
+		p.MustParse(s)
+
+		v, err := p.Resolve(value)
+		if assert.NoError(err, s) {
+			assert.Equal(expected, v, s)
+		}
+	}
+
+	assertResolveError := func(expectedErr error, expectedPointer, s string) {
+		t.Helper()
+
+		var p Pointer
+		p.MustParse(s)
+
+		_, err := p.Resolve(value)
+
+		var pointerErr *PointerError
+		if assert.ErrorAs(err, &pointerErr, s) {
+			assert.ErrorIs(err, expectedErr, s)
+			assert.Equal(expectedPointer, pointerErr.Pointer.String(), s)
+		}
+	}
+
+	assertResolve(value, "")
+	assertResolve(1.0, "/a/0")
+	assertResolve(2.0, "/a/1/b")
+	assertResolve(nil, "/c")
+
+	assertResolveError(ErrMemberNotFound, "/x", "/x/y")
+	assertResolveError(ErrArrayIndexOutOfRange, "/a/2", "/a/2")
+	assertResolveError(ErrInvalidArrayIndex, "/a/x", "/a/x/y")
+	assertResolveError(ErrNotArrayOrObject, "/a/0/b", "/a/0/b")
+	assertResolveError(ErrNotArrayOrObject, "/c/d", "/c/d")
+	assertResolveError(ErrInvalidArrayIndex, "/a/01", "/a/01")
+	assertResolveError(ErrInvalidArrayIndex, "/a/+1", "/a/+1")
+	assertResolveError(ErrArrayIndexOutOfRange, "/a/-", "/a/-")
+}
+
+func TestPointerModification(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"a": [1, {"b": 2}]}`), &value)
+
+	var err error
+
+	modify := func(fn func(Pointer, interface{}) (interface{}, error), s string) {
+		t.Helper()
+
+		var p Pointer
+		p.MustParse(s)
+
+		value, err = fn(p, value)
+		assert.NoError(err, s)
+	}
+
+	set := func(v interface{}) func(Pointer, interface{}) (interface{}, error) {
+		return func(p Pointer, value interface{}) (interface{}, error) {
+			return p.Set(value, v)
+		}
+	}
+
+	insert := func(v interface{}) func(Pointer, interface{}) (interface{}, error) {
+		return func(p Pointer, value interface{}) (interface{}, error) {
+			return p.Insert(value, v)
+		}
+	}
+
+	del := Pointer.Delete
+
+	modify(set(3.0), "/a/1/b")
+	modify(set("x"), "/a/1/c")
+	modify(insert(0.0), "/a/0")
+	modify(insert(4.0), "/a/3")
+	modify(set(true), "/d")
+	assert.Equal(map[string]interface{}{
+		"a": []interface{}{
+			0.0, 1.0, map[string]interface{}{"b": 3.0, "c": "x"}, 4.0,
+		},
+		"d": true,
+	}, value)
+
+	modify(del, "/a/1")
+	modify(del, "/a/1/b")
+	modify(del, "/d")
+	assert.Equal(map[string]interface{}{
+		"a": []interface{}{0.0, map[string]interface{}{"c": "x"}, 4.0},
+	}, value)
+
+	_, err = NewPointer("a", 3).Set(value, 1)
+	assert.ErrorIs(err, ErrArrayIndexOutOfRange)
+
+	_, err = NewPointer("a", 4).Insert(value, 1)
+	assert.ErrorIs(err, ErrArrayIndexOutOfRange)
+
+	_, err = NewPointer("x").Delete(value)
+	assert.ErrorIs(err, ErrMemberNotFound)
+
+	_, err = NewPointer().Delete(value)
+	assert.ErrorIs(err, ErrEmptyPointer)
+
+	modify(insert(5.0), "/a/-")
+	modify(set(6.0), "/a/-")
+	assert.Equal(map[string]interface{}{
+		"a": []interface{}{
+			0.0, map[string]interface{}{"c": "x"}, 4.0, 5.0, 6.0,
+		},
+	}, value)
+
+	_, err = NewPointer("a", AppendToken).Delete(value)
+	assert.ErrorIs(err, ErrArrayIndexOutOfRange)
+}
+
+func TestPointerModificationArrayCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	array := []interface{}{1.0, 2.0, 3.0}
+	object := map[string]interface{}{"a": array}
+
+	value, err := NewPointer("a", 0).Delete(object)
+	if assert.NoError(err) {
+		assert.Equal(map[string]interface{}{
+			"a": []interface{}{2.0, 3.0},
+		}, value)
+	}
+	assert.Equal([]interface{}{1.0, 2.0, 3.0}, array)
+
+	array = make([]interface{}, 3, 4)
+	copy(array, []interface{}{1.0, 2.0, 3.0})
+
+	value, err = NewPointer(1).Insert(array, 4.0)
+	if assert.NoError(err) {
+		assert.Equal([]interface{}{1.0, 4.0, 2.0, 3.0}, value)
+	}
+	assert.Equal([]interface{}{1.0, 2.0, 3.0}, array)
+	assert.Nil(array[:4][3])
+
+	value, err = NewPointer(AppendToken).Set(array, 5.0)
+	if assert.NoError(err) {
+		assert.Equal([]interface{}{1.0, 2.0, 3.0, 5.0}, value)
+	}
+	assert.Nil(array[:4][3])
+}
+
+func TestToken(t *testing.T) {
+	assert := assert.New(t)
+
+	assertArrayIndex := func(expected int, token Token) {
+		t.Helper()
+
+		i, ok := token.ArrayIndex()
+		if assert.True(ok, token) {
+			assert.Equal(expected, i, token)
+		}
+	}
+
+	assertArrayIndex(0, "0")
+	assertArrayIndex(7, "7")
+	assertArrayIndex(42, "42")
+
+	assert.False(Token("").IsArrayIndex())
+	assert.False(Token("-").IsArrayIndex())
+	assert.False(Token("00").IsArrayIndex())
+	assert.False(Token("01").IsArrayIndex())
+	assert.False(Token("+1").IsArrayIndex())
+	assert.False(Token("-1").IsArrayIndex())
+	assert.False(Token("1e2").IsArrayIndex())
+
+	assert.True(Token("-").IsAppend())
+	assert.False(Token("0").IsAppend())
+
+	assert.Equal(Pointer{"a", "-"}, NewPointer(Token("a"), AppendToken))
+}
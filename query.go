@@ -0,0 +1,830 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query is a JSONPath expression (RFC 9535) which can be evaluated on generic
+// JSON values. The following syntax is supported:
+//
+// - Child segments: $.name, $['name'], $[0], $[-1], $.*, $[*].
+// - Multiple selectors: $['a', 'b'], $[0, 2].
+// - Array slices: $[1:3], $[::2], $[::-1].
+// - Descendant segments: $..name, $..*, $..[0].
+// - Filters: $[?@.price > 10 && @.tags], $[?(!@.disabled)].
+//
+// Filters support comparisons (==, !=, <, <=, >, >=) between literals and
+// singular queries relative to the current node (@) or to the root ($), and
+// existence tests. Function extensions are not supported.
+//
+// Object members are visited in lexicographic order so that results are
+// deterministic.
+type Query struct {
+	source   string
+	segments []querySegment
+}
+
+type QueryResult struct {
+	Pointer Pointer
+	Value   interface{}
+}
+
+type QuerySyntaxError struct {
+	Offset  int
+	Message string
+}
+
+func (err *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at offset %d: %s", err.Offset,
+		err.Message)
+}
+
+type querySegment struct {
+	descendant bool
+	selectors  []querySelector
+}
+
+type querySelector interface {
+	selectNodes(QueryResult, interface{}, []QueryResult) []QueryResult
+}
+
+type queryNameSelector string
+
+type queryWildcardSelector struct{}
+
+type queryIndexSelector int
+
+type querySliceSelector struct {
+	start, end *int
+	step       int
+}
+
+type queryFilterSelector struct {
+	expr queryFilterExpr
+}
+
+type queryFilterExpr interface {
+	evaluate(current, root interface{}) bool
+}
+
+type queryOrExpr []queryFilterExpr
+
+type queryAndExpr []queryFilterExpr
+
+type queryNotExpr struct {
+	expr queryFilterExpr
+}
+
+type queryTestExpr struct {
+	path *queryPath
+}
+
+type queryComparisonExpr struct {
+	op          string
+	left, right queryOperand
+}
+
+type queryOperand interface {
+	operandValue(current, root interface{}) (interface{}, bool)
+}
+
+type queryLiteral struct {
+	value interface{}
+}
+
+type queryPath struct {
+	relative bool
+	segments []querySegment
+}
+
+func (q *Query) Parse(s string) error {
+	p := queryParser{s: s}
+
+	segments, err := p.parseQuery()
+	if err != nil {
+		return err
+	}
+
+	*q = Query{source: s, segments: segments}
+	return nil
+}
+
+func (q *Query) MustParse(s string) {
+	if err := q.Parse(s); err != nil {
+		panic(fmt.Sprintf("invalid json query %q: %v", s, err))
+	}
+}
+
+func (q Query) String() string {
+	return q.source
+}
+
+func (q Query) Evaluate(value interface{}) []QueryResult {
+	root := QueryResult{Pointer: Pointer{}, Value: value}
+	return evaluateQuerySegments(q.segments, root, value)
+}
+
+func evaluateQuerySegments(segments []querySegment, node QueryResult, root interface{}) []QueryResult {
+	nodes := []QueryResult{node}
+
+	for _, segment := range segments {
+		var nodes2 []QueryResult
+
+		for _, node := range nodes {
+			nodes2 = segment.apply(node, root, nodes2)
+		}
+
+		nodes = nodes2
+	}
+
+	return nodes
+}
+
+func (s *querySegment) apply(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	for _, selector := range s.selectors {
+		results = selector.selectNodes(node, root, results)
+	}
+
+	if s.descendant {
+		for _, child := range queryChildren(node) {
+			results = s.apply(child, root, results)
+		}
+	}
+
+	return results
+}
+
+func queryChildren(node QueryResult) []QueryResult {
+	switch v := node.Value.(type) {
+	case []interface{}:
+		children := make([]QueryResult, len(v))
+		for i, element := range v {
+			children[i] = QueryResult{node.Pointer.Child(i), element}
+		}
+
+		return children
+
+	case map[string]interface{}:
+		children := make([]QueryResult, 0, len(v))
+		for _, name := range sortedMemberNames(v) {
+			children = append(children,
+				QueryResult{node.Pointer.Child(name), v[name]})
+		}
+
+		return children
+	}
+
+	return nil
+}
+
+func (s queryNameSelector) selectNodes(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	if obj, ok := node.Value.(map[string]interface{}); ok {
+		if value, found := obj[string(s)]; found {
+			child := QueryResult{node.Pointer.Child(string(s)), value}
+			results = append(results, child)
+		}
+	}
+
+	return results
+}
+
+func (s queryWildcardSelector) selectNodes(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	return append(results, queryChildren(node)...)
+}
+
+func (s queryIndexSelector) selectNodes(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	if array, ok := node.Value.([]interface{}); ok {
+		i := int(s)
+		if i < 0 {
+			i += len(array)
+		}
+
+		if i >= 0 && i < len(array) {
+			results = append(results,
+				QueryResult{node.Pointer.Child(i), array[i]})
+		}
+	}
+
+	return results
+}
+
+func (s querySliceSelector) selectNodes(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	array, ok := node.Value.([]interface{})
+	if !ok || s.step == 0 {
+		return results
+	}
+
+	n := len(array)
+
+	bound := func(i *int, defaultValue, min, max int) int {
+		if i == nil {
+			return defaultValue
+		}
+
+		j := *i
+		if j < 0 {
+			j += n
+		}
+
+		if j < min {
+			return min
+		} else if j > max {
+			return max
+		}
+
+		return j
+	}
+
+	add := func(i int) {
+		results = append(results, QueryResult{node.Pointer.Child(i), array[i]})
+	}
+
+	if s.step > 0 {
+		lower := bound(s.start, 0, 0, n)
+		upper := bound(s.end, n, 0, n)
+
+		for i := lower; i < upper; i += s.step {
+			add(i)
+		}
+	} else {
+		upper := bound(s.start, n-1, -1, n-1)
+		lower := bound(s.end, -1, -1, n-1)
+
+		for i := upper; i > lower; i += s.step {
+			add(i)
+		}
+	}
+
+	return results
+}
+
+func (s queryFilterSelector) selectNodes(node QueryResult, root interface{}, results []QueryResult) []QueryResult {
+	for _, child := range queryChildren(node) {
+		if s.expr.evaluate(child.Value, root) {
+			results = append(results, child)
+		}
+	}
+
+	return results
+}
+
+func (e queryOrExpr) evaluate(current, root interface{}) bool {
+	for _, expr := range e {
+		if expr.evaluate(current, root) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e queryAndExpr) evaluate(current, root interface{}) bool {
+	for _, expr := range e {
+		if !expr.evaluate(current, root) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e queryNotExpr) evaluate(current, root interface{}) bool {
+	return !e.expr.evaluate(current, root)
+}
+
+func (e queryTestExpr) evaluate(current, root interface{}) bool {
+	return len(e.path.evaluate(current, root)) > 0
+}
+
+func (e queryComparisonExpr) evaluate(current, root interface{}) bool {
+	left, leftFound := e.left.operandValue(current, root)
+	right, rightFound := e.right.operandValue(current, root)
+
+	equal := func() bool {
+		if !leftFound || !rightFound {
+			return leftFound == rightFound
+		}
+
+		return Equal(left, right)
+	}
+
+	less := func(v1, v2 interface{}) bool {
+		if !leftFound || !rightFound {
+			return false
+		}
+
+		switch {
+		case IsNumber(v1) && IsNumber(v2):
+			return AsNumber(v1) < AsNumber(v2)
+
+		case IsString(v1) && IsString(v2):
+			return AsString(v1) < AsString(v2)
+		}
+
+		return false
+	}
+
+	switch e.op {
+	case "==":
+		return equal()
+	case "!=":
+		return !equal()
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || equal()
+	case ">":
+		return less(right, left)
+	case ">=":
+		return less(right, left) || equal()
+	}
+
+	panic(fmt.Sprintf("unknown comparison operator %q", e.op))
+}
+
+func (l queryLiteral) operandValue(current, root interface{}) (interface{}, bool) {
+	return l.value, true
+}
+
+func (p *queryPath) evaluate(current, root interface{}) []QueryResult {
+	value := root
+	if p.relative {
+		value = current
+	}
+
+	node := QueryResult{Pointer: Pointer{}, Value: value}
+	return evaluateQuerySegments(p.segments, node, root)
+}
+
+func (p *queryPath) operandValue(current, root interface{}) (interface{}, bool) {
+	nodes := p.evaluate(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0].Value, true
+}
+
+type queryParser struct {
+	s   string
+	pos int
+}
+
+func (p *queryParser) parseQuery() ([]querySegment, error) {
+	if !p.skip("$") {
+		return nil, p.syntaxError("query must start with '$'")
+	}
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.s) {
+		return nil, p.syntaxError("unexpected character %q", p.s[p.pos])
+	}
+
+	return segments, nil
+}
+
+func (p *queryParser) parseSegments() ([]querySegment, error) {
+	var segments []querySegment
+
+	for {
+		start := p.pos
+		p.skipWhitespaces()
+
+		var segment querySegment
+
+		switch {
+		case p.skip(".."):
+			segment.descendant = true
+
+			var err error
+
+			switch {
+			case p.peek() == '[':
+				segment.selectors, err = p.parseBracketedSelectors()
+			case p.skip("*"):
+				segment.selectors = []querySelector{queryWildcardSelector{}}
+			default:
+				var name string
+				name, err = p.parseMemberName()
+				segment.selectors = []querySelector{queryNameSelector(name)}
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+		case p.skip("."):
+			if p.skip("*") {
+				segment.selectors = []querySelector{queryWildcardSelector{}}
+			} else {
+				name, err := p.parseMemberName()
+				if err != nil {
+					return nil, err
+				}
+
+				segment.selectors = []querySelector{queryNameSelector(name)}
+			}
+
+		case p.peek() == '[':
+			selectors, err := p.parseBracketedSelectors()
+			if err != nil {
+				return nil, err
+			}
+
+			segment.selectors = selectors
+
+		default:
+			p.pos = start
+			return segments, nil
+		}
+
+		segments = append(segments, segment)
+	}
+}
+
+func (p *queryParser) parseMemberName() (string, error) {
+	start := p.pos
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+
+		if c == '_' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+
+		break
+	}
+
+	if p.pos == start {
+		return "", p.syntaxError("missing member name")
+	}
+
+	return p.s[start:p.pos], nil
+}
+
+func (p *queryParser) parseBracketedSelectors() ([]querySelector, error) {
+	p.skip("[")
+
+	var selectors []querySelector
+
+	for {
+		p.skipWhitespaces()
+
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+
+		p.skipWhitespaces()
+
+		if p.skip("]") {
+			return selectors, nil
+		}
+
+		if !p.skip(",") {
+			return nil, p.syntaxError("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *queryParser) parseSelector() (querySelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		return queryNameSelector(name), nil
+
+	case c == '*':
+		p.pos++
+		return queryWildcardSelector{}, nil
+
+	case c == '?':
+		p.pos++
+		p.skipWhitespaces()
+
+		expr, err := p.parseOrExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		return queryFilterSelector{expr: expr}, nil
+
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	}
+
+	return nil, p.syntaxError("invalid selector")
+}
+
+func (p *queryParser) parseIndexOrSlice() (querySelector, error) {
+	var values [3]*int
+
+	for i := 0; i < 3; i++ {
+		p.skipWhitespaces()
+
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			n, err := p.parseInteger()
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = &n
+		}
+
+		p.skipWhitespaces()
+
+		if i == 0 && p.peek() != ':' {
+			if values[0] == nil {
+				return nil, p.syntaxError("missing array index")
+			}
+
+			return queryIndexSelector(*values[0]), nil
+		}
+
+		if i == 2 || !p.skip(":") {
+			break
+		}
+	}
+
+	slice := querySliceSelector{start: values[0], end: values[1], step: 1}
+	if values[2] != nil {
+		slice.step = *values[2]
+	}
+
+	return slice, nil
+}
+
+func (p *queryParser) parseInteger() (int, error) {
+	start := p.pos
+
+	p.skip("-")
+
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+
+	i, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.syntaxError("invalid integer")
+	}
+
+	return i, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	start := p.pos
+
+	quote := p.s[p.pos]
+	p.pos++
+
+	// Convert the literal to a JSON string so that we can rely on
+	// encoding/json to process escape sequences.
+	var buf strings.Builder
+	buf.WriteByte('"')
+
+	for {
+		if p.pos >= len(p.s) {
+			p.pos = start
+			return "", p.syntaxError("unterminated string")
+		}
+
+		c := p.s[p.pos]
+
+		switch {
+		case c == quote:
+			p.pos++
+			buf.WriteByte('"')
+
+			var s string
+			if err := json.Unmarshal([]byte(buf.String()), &s); err != nil {
+				p.pos = start
+				return "", p.syntaxError("invalid string")
+			}
+
+			return s, nil
+
+		case c == '\\':
+			if p.pos+1 >= len(p.s) {
+				p.pos = start
+				return "", p.syntaxError("unterminated string")
+			}
+
+			if c2 := p.s[p.pos+1]; c2 == '\'' {
+				buf.WriteByte('\'')
+			} else {
+				buf.WriteByte('\\')
+				buf.WriteByte(c2)
+			}
+
+			p.pos += 2
+
+		case c == '"':
+			buf.WriteString(`\"`)
+			p.pos++
+
+		default:
+			buf.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *queryParser) parseOrExpr() (queryFilterExpr, error) {
+	var exprs queryOrExpr
+
+	for {
+		expr, err := p.parseAndExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, expr)
+
+		p.skipWhitespaces()
+		if !p.skip("||") {
+			break
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return exprs, nil
+}
+
+func (p *queryParser) parseAndExpr() (queryFilterExpr, error) {
+	var exprs queryAndExpr
+
+	for {
+		expr, err := p.parseBasicExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, expr)
+
+		p.skipWhitespaces()
+		if !p.skip("&&") {
+			break
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return exprs, nil
+}
+
+func (p *queryParser) parseBasicExpr() (queryFilterExpr, error) {
+	p.skipWhitespaces()
+
+	switch {
+	case p.skip("!"):
+		expr, err := p.parseBasicExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		return queryNotExpr{expr: expr}, nil
+
+	case p.skip("("):
+		expr, err := p.parseOrExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipWhitespaces()
+		if !p.skip(")") {
+			return nil, p.syntaxError("expected ')'")
+		}
+
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespaces()
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.skip(op) {
+			p.skipWhitespaces()
+
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+
+			return queryComparisonExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	path, ok := left.(*queryPath)
+	if !ok {
+		return nil, p.syntaxError("literals cannot be used as tests")
+	}
+
+	return queryTestExpr{path: path}, nil
+}
+
+func (p *queryParser) parseOperand() (queryOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+
+		return &queryPath{relative: c == '@', segments: segments}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		return queryLiteral{value: s}, nil
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+
+	case p.skip("true"):
+		return queryLiteral{value: true}, nil
+
+	case p.skip("false"):
+		return queryLiteral{value: false}, nil
+
+	case p.skip("null"):
+		return queryLiteral{value: nil}, nil
+	}
+
+	return nil, p.syntaxError("invalid operand")
+}
+
+func (p *queryParser) parseNumber() (queryOperand, error) {
+	start := p.pos
+
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	var f float64
+	if err := json.Unmarshal([]byte(p.s[start:p.pos]), &f); err != nil {
+		p.pos = start
+		return nil, p.syntaxError("invalid number")
+	}
+
+	return queryLiteral{value: f}, nil
+}
+
+func (p *queryParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+func (p *queryParser) skip(s string) bool {
+	if strings.HasPrefix(p.s[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+func (p *queryParser) skipWhitespaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *queryParser) syntaxError(format string, args ...interface{}) error {
+	return &QuerySyntaxError{
+		Offset:  p.pos,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,119 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	err := Unmarshal([]byte(`{
+  "store": {
+    "items": [
+      {"id": "a", "price": 5, "tags": ["x"]},
+      {"id": "b", "price": 12},
+      {"id": "c", "price": 20, "tags": [], "disabled": true}
+    ],
+    "owner": {"name": "bob", "id": "o"}
+  }
+}`), &value)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assertQuery := func(expected []string, expectedValues []interface{}, s string) {
+		t.Helper()
+
+		var q Query
+		if !assert.NoError(q.Parse(s), s) {
+			return
+		}
+
+		results := q.Evaluate(value)
+
+		pointers := make([]string, len(results))
+		values := make([]interface{}, len(results))
+		for i, result := range results {
+			pointers[i] = result.Pointer.String()
+			values[i] = result.Value
+		}
+
+		assert.Equal(expected, pointers, s)
+
+		if expectedValues != nil {
+			assert.Equal(expectedValues, values, s)
+		}
+	}
+
+	assertQuery([]string{""}, nil, "$")
+	assertQuery([]string{"/store/owner/name"}, []interface{}{"bob"},
+		"$.store.owner.name")
+	assertQuery([]string{"/store/owner/name"}, nil,
+		`$['store']["owner"]['name']`)
+	assertQuery([]string{"/store/items/2"}, nil, "$.store.items[-1]")
+	assertQuery([]string{}, nil, "$.store.items[3]")
+	assertQuery([]string{"/store/items", "/store/owner"}, nil, "$.store.*")
+	assertQuery([]string{"/store/items/0/id", "/store/items/2/id"},
+		[]interface{}{"a", "c"}, "$.store.items[0, 2].id")
+
+	assertQuery([]string{"/store/items/1", "/store/items/2"}, nil,
+		"$.store.items[1:]")
+	assertQuery([]string{"/store/items/0", "/store/items/2"}, nil,
+		"$.store.items[::2]")
+	assertQuery([]string{"/store/items/2", "/store/items/1",
+		"/store/items/0"}, nil, "$.store.items[::-1]")
+	assertQuery([]string{"/store/items/0"}, nil, "$.store.items[:-2]")
+
+	assertQuery([]string{"/store/items/0/id", "/store/items/1/id",
+		"/store/items/2/id", "/store/owner/id"}, nil, "$..id")
+	assertQuery([]string{"/store/items/0/tags/0"}, nil, "$..tags[0]")
+
+	assertQuery([]string{"/store/items/1/id", "/store/items/2/id"},
+		[]interface{}{"b", "c"}, "$.store.items[?(@.price > 10)].id")
+	assertQuery([]string{"/store/items/0", "/store/items/2"}, nil,
+		"$.store.items[?@.tags]")
+	assertQuery([]string{"/store/items/0", "/store/items/1"}, nil,
+		"$.store.items[?!@.disabled]")
+	assertQuery([]string{"/store/items/1"}, nil,
+		"$.store.items[?@.price >= 12 && !(@.disabled == true)]")
+	assertQuery([]string{"/store/items/0", "/store/items/2"}, nil,
+		"$.store.items[?@.price < 10 || @.id == 'c']")
+	assertQuery([]string{"/store/items/1"}, nil,
+		`$.store.items[?@.id == "b" && @.price != $.store.items[0].price]`)
+	assertQuery([]string{"/store/items/0", "/store/items/1"}, nil,
+		"$.store.items[?@.price <= 1.2e1]")
+}
+
+func TestQueryParse(t *testing.T) {
+	assert := assert.New(t)
+
+	assertParseError := func(expectedOffset int, s string) {
+		t.Helper()
+
+		var q Query
+		err := q.Parse(s)
+
+		var syntaxErr *QuerySyntaxError
+		if assert.ErrorAs(err, &syntaxErr, s) {
+			assert.Equal(expectedOffset, syntaxErr.Offset, s)
+		}
+	}
+
+	assertParseError(0, "")
+	assertParseError(0, "a.b")
+	assertParseError(2, "$.")
+	assertParseError(4, "$.a[")
+	assertParseError(4, "$[1 2]")
+	assertParseError(2, "$['a]")
+	assertParseError(7, "$[?@ > ]")
+	assertParseError(8, "$[?(@.a ]")
+	assertParseError(5, "$[?42]")
+	assertParseError(3, "$.a)")
+
+	var q Query
+	q.MustParse("$.a[*]")
+	assert.Equal("$.a[*]", q.String())
+}
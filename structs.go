@@ -0,0 +1,334 @@
+package ejson
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The structure metadata follow the rules used by encoding/json to map
+// structure fields to object members, so that names reported by EJSON always
+// match the wire format.
+
+type structInfo struct {
+	Fields       []*structField
+	FieldsByName map[string]*structField
+
+	// The index of the field tagged with `ejson:"any"` if there is one.
+	AnyFieldIndex []int
+}
+
+type structField struct {
+	Name      string
+	GoName    string
+	Index     []int
+	Type      reflect.Type
+	Tagged    bool
+	OmitEmpty bool
+	Quoted    bool
+
+	Options tagOptions
+
+	// The formats listed in the "time" option if there is one
+	TimeFormats []TimeFormat
+}
+
+type tagOptions map[string]string
+
+func parseTagOptions(tag string) tagOptions {
+	opts := make(tagOptions)
+
+	for _, part := range strings.Split(tag, ",") {
+		if part == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(part, "=")
+		opts[name] = value
+	}
+
+	return opts
+}
+
+func (opts tagOptions) Has(name string) bool {
+	_, found := opts[name]
+	return found
+}
+
+var structInfos sync.Map // reflect.Type -> *structInfo
+
+func getStructInfo(t reflect.Type) *structInfo {
+	if info, found := structInfos.Load(t); found {
+		return info.(*structInfo)
+	}
+
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("type %v is not a structure", t))
+	}
+
+	info, _ := structInfos.LoadOrStore(t, buildStructInfo(t))
+	return info.(*structInfo)
+}
+
+func buildStructInfo(t reflect.Type) *structInfo {
+	var info structInfo
+
+	type queuedStruct struct {
+		Type  reflect.Type
+		Index []int
+	}
+
+	var fields []*structField
+
+	current := []queuedStruct{}
+	next := []queuedStruct{{Type: t}}
+	visited := make(map[reflect.Type]bool)
+
+	for len(next) > 0 {
+		current, next = next, nil
+
+		for _, s := range current {
+			if visited[s.Type] {
+				continue
+			}
+			visited[s.Type] = true
+
+			for i := 0; i < s.Type.NumField(); i++ {
+				sf := s.Type.Field(i)
+
+				index := make([]int, len(s.Index)+1)
+				copy(index, s.Index)
+				index[len(s.Index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				opts := parseTagOptions(sf.Tag.Get("ejson"))
+
+				if opts.Has("any") {
+					if sf.Type != anyMapType {
+						panic(fmt.Sprintf("field %s of type %v tagged as "+
+							"\"any\" is not of type %v", sf.Name, s.Type,
+							anyMapType))
+					}
+
+					if info.AnyFieldIndex == nil {
+						info.AnyFieldIndex = index
+					}
+
+					continue
+				}
+
+				jsonTag := sf.Tag.Get("json")
+				if jsonTag == "-" {
+					continue
+				}
+
+				name, jsonOpts, _ := strings.Cut(jsonTag, ",")
+				jsonOptions := parseTagOptions(jsonOpts)
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, queuedStruct{Type: ft, Index: index})
+					continue
+				}
+
+				field := structField{
+					Name:      name,
+					GoName:    sf.Name,
+					Index:     index,
+					Type:      sf.Type,
+					Tagged:    name != "",
+					OmitEmpty: jsonOptions.Has("omitempty"),
+					Options:   opts,
+				}
+
+				if field.Name == "" {
+					field.Name = sf.Name
+				}
+
+				if opts.Has("time") {
+					if !isTimeFieldType(sf.Type) {
+						panic(fmt.Sprintf("ejson tag option \"time\" cannot "+
+							"be used with field %s of type %v", sf.Name,
+							sf.Type))
+					}
+
+					field.TimeFormats = parseTimeFormats(opts["time"])
+				}
+
+				if jsonOptions.Has("string") {
+					switch ft.Kind() {
+					case reflect.Bool,
+						reflect.Int, reflect.Int8, reflect.Int16,
+						reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16,
+						reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+						reflect.Float32, reflect.Float64,
+						reflect.String:
+						field.Quoted = true
+					}
+				}
+
+				fields = append(fields, &field)
+			}
+		}
+	}
+
+	// Resolve name conflicts the same way encoding/json does: the shallowest
+	// field wins, then the tagged one; if there is still an ambiguity, all
+	// fields are dropped.
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		fi, fj := fields[i], fields[j]
+
+		if fi.Name != fj.Name {
+			return fi.Name < fj.Name
+		}
+
+		if len(fi.Index) != len(fj.Index) {
+			return len(fi.Index) < len(fj.Index)
+		}
+
+		return fi.Tagged && !fj.Tagged
+	})
+
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].Name == fields[i].Name {
+			j++
+		}
+
+		group := fields[i:j]
+		if len(group) == 1 || len(group[0].Index) < len(group[1].Index) ||
+			group[0].Tagged != group[1].Tagged {
+			info.Fields = append(info.Fields, group[0])
+		}
+
+		i = j
+	}
+
+	sort.Slice(info.Fields, func(i, j int) bool {
+		return lessIndex(info.Fields[i].Index, info.Fields[j].Index)
+	})
+
+	info.FieldsByName = make(map[string]*structField, len(info.Fields))
+	for _, field := range info.Fields {
+		info.FieldsByName[field.Name] = field
+	}
+
+	return &info
+}
+
+// Field returns the name of the object member associated with a structure
+// field when encoding or decoding JSON data. The value is either a structure
+// or a pointer to a structure, and can be nil. This function is useful to
+// report validation errors using the names of the wire format, e.g.:
+//
+//	v.CheckStringNotEmpty(ejson.Field(u, "UserName"), u.UserName)
+func Field(value interface{}, name string) string {
+	t := reflect.TypeOf(value)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("value %#v (%T) is not a structure or a pointer "+
+			"to a structure", value, value))
+	}
+
+	info := getStructInfo(t)
+
+	var field *structField
+	for _, f := range info.Fields {
+		if f.GoName == name && (field == nil || len(f.Index) < len(field.Index)) {
+			field = f
+		}
+	}
+
+	if field == nil {
+		panic(fmt.Sprintf("type %v does not have any field %q encoded in "+
+			"json data", t, name))
+	}
+
+	return field.Name
+}
+
+func (info *structInfo) Field(name string) *structField {
+	if field, found := info.FieldsByName[name]; found {
+		return field
+	}
+
+	// Member names are matched case-insensitively as a fallback, as done by
+	// encoding/json.
+	for _, field := range info.Fields {
+		if strings.EqualFold(field.Name, name) {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func lessIndex(i1, i2 []int) bool {
+	for i := 0; i < len(i1) && i < len(i2); i++ {
+		if i1[i] != i2[i] {
+			return i1[i] < i2[i]
+		}
+	}
+
+	return len(i1) < len(i2)
+}
+
+// Return the value of a field, or an invalid value if the field is part of a
+// null embedded structure pointer.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, fi := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(fi)
+	}
+
+	return v
+}
+
+// Return the value of a field, allocating null embedded structure pointers on
+// the way.
+func settableFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, fi := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(fi)
+	}
+
+	return v
+}
+
+var anyMapType = reflect.TypeOf(map[string]interface{}{})
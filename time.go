@@ -0,0 +1,403 @@
+package ejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time values are decoded from RFC 3339 strings or from numbers of seconds
+// since the Unix epoch. Accepted formats can be restricted for a specific
+// structure field using the "time" option of the ejson tag, e.g.
+// `ejson:"time=unix_ms|rfc3339"`; numbers are then interpreted using the first
+// numeric format of the list.
+//
+// The option can also be used for arrays, slices and maps of Time values,
+// e.g. `ejson:"time=unix"` for a []Time field.
+//
+// A value decoded from a tagged field by Unmarshal or DecodeValue keeps the
+// first format of the list and is encoded using it. Encoding does not have
+// access to struct tags: values created with a zero format, either in Go code
+// or by decoding an untagged field, are encoded as RFC 3339 strings whatever
+// the tag of the field. Use NewTime to select the output format of values
+// created in Go code.
+
+type TimeFormat string
+
+const (
+	TimeFormatRFC3339   TimeFormat = "rfc3339"
+	TimeFormatUnix      TimeFormat = "unix"
+	TimeFormatUnixMilli TimeFormat = "unix_ms"
+)
+
+var timeInputFormats = []TimeFormat{TimeFormatRFC3339, TimeFormatUnix}
+
+type Time struct {
+	time.Time
+
+	format TimeFormat
+}
+
+var timeType = reflect.TypeOf(Time{})
+
+func NewTime(t time.Time, format TimeFormat) Time {
+	return Time{Time: t, format: format}
+}
+
+func (t Time) JSONFormat() TimeFormat {
+	if t.format == "" {
+		return TimeFormatRFC3339
+	}
+
+	return t.format
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	switch format := t.JSONFormat(); format {
+	case TimeFormatRFC3339:
+		return json.Marshal(t.Time.Format(time.RFC3339Nano))
+
+	case TimeFormatUnix:
+		return []byte(formatUnixTime(t.Time)), nil
+
+	case TimeFormatUnixMilli:
+		return []byte(strconv.FormatInt(t.UnixMilli(), 10)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown time format %q", format)
+	}
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	t2, err := parseTime(value, timeInputFormats)
+	if err != nil {
+		return &json.UnmarshalTypeError{
+			Value: jsonValueDescription(value),
+			Type:  timeType,
+		}
+	}
+
+	*t = Time{Time: t2}
+	return nil
+}
+
+func (t Time) checkJSONValue(value interface{}) *ValidationError {
+	return checkTimeValue(value, timeInputFormats)
+}
+
+func checkTimeValue(value interface{}, formats []TimeFormat) *ValidationError {
+	if _, err := parseTime(value, formats); err != nil {
+		return &ValidationError{
+			Code:    "invalid_time",
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func parseTimeFormats(s string) []TimeFormat {
+	var formats []TimeFormat
+
+	for _, name := range strings.Split(s, "|") {
+		format := TimeFormat(name)
+
+		switch format {
+		case TimeFormatRFC3339, TimeFormatUnix, TimeFormatUnixMilli:
+			formats = append(formats, format)
+
+		default:
+			panic(fmt.Sprintf("unknown time format %q", name))
+		}
+	}
+
+	return formats
+}
+
+func parseTime(value interface{}, formats []TimeFormat) (time.Time, error) {
+	for _, format := range formats {
+		switch format {
+		case TimeFormatRFC3339:
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid rfc 3339 timestamp")
+			}
+
+			return t, nil
+
+		case TimeFormatUnix, TimeFormatUnixMilli:
+			var s string
+
+			switch v := value.(type) {
+			case float64:
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					return time.Time{}, fmt.Errorf("invalid timestamp")
+				}
+
+				s = strconv.FormatFloat(v, 'f', -1, 64)
+
+			case json.Number:
+				s = v.String()
+
+			default:
+				continue
+			}
+
+			t, err := parseUnixTime(s, format == TimeFormatUnixMilli)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid timestamp")
+			}
+
+			return t, nil
+		}
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString("timestamp must be in one of the following formats: ")
+
+	for i, format := range formats {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(string(format))
+	}
+
+	return time.Time{}, fmt.Errorf("%s", buf.String())
+}
+
+// Parse a decimal number of seconds or milliseconds since the Unix epoch.
+// The integer and fractional parts are parsed separately since float64 values
+// cannot represent current timestamps with a nanosecond precision.
+func parseUnixTime(s string, milliseconds bool) (time.Time, error) {
+	if strings.ContainsAny(s, "eE") {
+		// Numbers in exponent notation have no exact decimal representation
+		// we could parse, so go through a float64 value
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	i, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Number of digits of the fractional part for a nanosecond precision
+	nbDigits := 9
+	unit := time.Second
+	if milliseconds {
+		nbDigits = 6
+		unit = time.Millisecond
+	}
+
+	if len(fracPart) > nbDigits {
+		fracPart = fracPart[:nbDigits]
+	}
+
+	var nsec int64
+	if fracPart != "" {
+		nsec, err = strconv.ParseInt(fracPart, 10, 64)
+		if err != nil || nsec < 0 {
+			return time.Time{}, fmt.Errorf("invalid fractional part")
+		}
+
+		for n := len(fracPart); n < nbDigits; n++ {
+			nsec *= 10
+		}
+	}
+
+	unitsPerSecond := int64(time.Second / unit)
+
+	sec := i / unitsPerSecond
+	nsec += i % unitsPerSecond * int64(unit)
+
+	if negative {
+		sec, nsec = -sec, -nsec
+	}
+
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+// Format a time as a decimal number of seconds since the Unix epoch with
+// integer arithmetic so that the nanosecond precision is kept.
+func formatUnixTime(t time.Time) string {
+	sec := t.Unix()
+	nsec := int64(t.Nanosecond())
+
+	if nsec == 0 {
+		return strconv.FormatInt(sec, 10)
+	}
+
+	sign := ""
+	if sec < 0 {
+		// The nanosecond part is always positive, e.g. -1.5s is represented
+		// as -2s + 0.5s
+		sign = "-"
+		sec = -(sec + 1)
+		nsec = int64(time.Second) - nsec
+	}
+
+	frac := fmt.Sprintf("%09d", nsec)
+	frac = strings.TrimRight(frac, "0")
+
+	return sign + strconv.FormatInt(sec, 10) + "." + frac
+}
+
+// Check that a type can be used for a field tagged with the "time" option,
+// i.e. that it is a Time value or an array, slice or map of Time values,
+// possibly through pointers.
+func isTimeFieldType(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Array, reflect.Slice:
+		return isTimeFieldType(t.Elem())
+
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isTimeFieldType(t.Elem())
+	}
+
+	return false
+}
+
+func checkTimeField(t reflect.Type, value interface{}, pointer Pointer, formats []TimeFormat, errs *ValidationErrors) {
+	if value == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		checkTimeField(t.Elem(), value, pointer, formats, errs)
+
+	case reflect.Array, reflect.Slice:
+		if array, ok := value.([]interface{}); ok {
+			for i, element := range array {
+				checkTimeField(t.Elem(), element, pointer.Child(i), formats,
+					errs)
+			}
+		}
+
+	case reflect.Map:
+		if obj, ok := value.(map[string]interface{}); ok {
+			for _, key := range sortedMemberNames(obj) {
+				checkTimeField(t.Elem(), obj[key], pointer.Child(key), formats,
+					errs)
+			}
+		}
+
+	default:
+		if err := checkTimeValue(value, formats); err != nil {
+			err.Pointer = pointer
+			*errs = append(*errs, err)
+		}
+	}
+}
+
+func decodeTimePass(dest reflect.Value, value interface{}, formats []TimeFormat) {
+	if value == nil {
+		return
+	}
+
+	switch dest.Kind() {
+	case reflect.Pointer:
+		if !dest.IsNil() {
+			decodeTimePass(dest.Elem(), value, formats)
+		}
+
+	case reflect.Array, reflect.Slice:
+		if array, ok := value.([]interface{}); ok {
+			for i := 0; i < len(array) && i < dest.Len(); i++ {
+				decodeTimePass(dest.Index(i), array[i], formats)
+			}
+		}
+
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok || dest.IsNil() {
+			return
+		}
+
+		for name, memberValue := range obj {
+			key := reflect.ValueOf(name).Convert(dest.Type().Key())
+
+			element := dest.MapIndex(key)
+			if !element.IsValid() {
+				continue
+			}
+
+			// Map elements are not addressable
+			element2 := reflect.New(element.Type()).Elem()
+			element2.Set(element)
+
+			decodeTimePass(element2, memberValue, formats)
+			dest.SetMapIndex(key, element2)
+		}
+
+	default:
+		// The value has already been checked before decoding
+		t, err := parseTime(value, formats)
+		if err != nil {
+			return
+		}
+
+		dest.Set(reflect.ValueOf(NewTime(t, formats[0])))
+	}
+}
+
+func (v *Validator) CheckTimeMin(token interface{}, t, min time.Time) bool {
+	if !t.Before(min) {
+		return true
+	}
+
+	v.AddError(token, "time_too_early",
+		"time must be equal or after %s", min.Format(time.RFC3339))
+	return false
+}
+
+func (v *Validator) CheckTimeMax(token interface{}, t, max time.Time) bool {
+	if !t.After(max) {
+		return true
+	}
+
+	v.AddError(token, "time_too_late",
+		"time must be equal or before %s", max.Format(time.RFC3339))
+	return false
+}
+
+func (v *Validator) CheckTimeMinMax(token interface{}, t, min, max time.Time) bool {
+	if !v.CheckTimeMin(token, t, min) {
+		return false
+	}
+
+	return v.CheckTimeMax(token, t, max)
+}
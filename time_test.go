@@ -0,0 +1,194 @@
+package ejson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestEvent struct {
+	Date      Time  `json:"date"`
+	Timestamp Time  `json:"timestamp" ejson:"time=unix_ms|rfc3339"`
+	Day       *Time `json:"day" ejson:"time=rfc3339"`
+}
+
+func (e *TestEvent) ValidateJSON(v *Validator) {
+	v.CheckTimeMin("date", e.Date.Time,
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTime(t *testing.T) {
+	assert := assert.New(t)
+
+	var event TestEvent
+	var validationErrs ValidationErrors
+
+	data := `{"date": 1700000000, "timestamp": 1700000000500,
+"day": "2024-01-02T03:04:05Z"}`
+	if assert.NoError(Unmarshal([]byte(data), &event)) {
+		assert.Equal(int64(1700000000), event.Date.Unix())
+		assert.Equal(int64(1700000000500), event.Timestamp.UnixMilli())
+		assert.Equal(2024, event.Day.Year())
+
+		data, err := json.Marshal(event)
+		if assert.NoError(err) {
+			assert.JSONEq(`{"date": "2023-11-14T22:13:20Z",
+"timestamp": 1700000000500, "day": "2024-01-02T03:04:05Z"}`, string(data))
+		}
+	}
+
+	event = TestEvent{}
+	err := Unmarshal([]byte(`{"date": "foo"}`), &event)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/date", validationErrs[0].Pointer.String())
+			assert.Equal("invalid_time", validationErrs[0].Code)
+		}
+	}
+
+	event = TestEvent{}
+	err = Unmarshal([]byte(`{"date": 0, "day": 1700000000}`), &event)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/day", validationErrs[0].Pointer.String())
+			assert.Equal("invalid_time", validationErrs[0].Code)
+		}
+	}
+
+	event = TestEvent{}
+	err = Unmarshal([]byte(`{"date": 0}`), &event)
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(1, len(validationErrs)) {
+			assert.Equal("/date", validationErrs[0].Pointer.String())
+			assert.Equal("time_too_early", validationErrs[0].Code)
+		}
+	}
+}
+
+func TestTimeContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	type Schedule struct {
+		Times     []Time           `json:"times" ejson:"time=unix_ms"`
+		TimeTable map[string]*Time `json:"time_table" ejson:"time=unix"`
+	}
+
+	data := `{"times": [1700000000500, null], "time_table": {"a": 1700000000}}`
+
+	var schedule Schedule
+	if assert.NoError(Unmarshal([]byte(data), &schedule)) {
+		if assert.Equal(2, len(schedule.Times)) {
+			assert.Equal(int64(1700000000500), schedule.Times[0].UnixMilli())
+		}
+
+		if assert.Contains(schedule.TimeTable, "a") {
+			assert.Equal(int64(1700000000), schedule.TimeTable["a"].Unix())
+		}
+
+		data2, err := json.Marshal(schedule)
+		if assert.NoError(err) {
+			assert.JSONEq(`{"times": [1700000000500, "0001-01-01T00:00:00Z"],
+"time_table": {"a": 1700000000}}`, string(data2))
+		}
+	}
+
+	var value interface{}
+	if err := Unmarshal([]byte(data), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	schedule = Schedule{}
+	if assert.NoError(DecodeValue(value, &schedule)) {
+		assert.Equal(int64(1700000000500), schedule.Times[0].UnixMilli())
+		assert.Equal(int64(1700000000), schedule.TimeTable["a"].Unix())
+	}
+
+	var validationErrs ValidationErrors
+
+	err := Unmarshal([]byte(`{"times": [0, "2024-01-02T03:04:05Z"],
+"time_table": {"b": "foo"}}`), &schedule)
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+			assert.Equal("invalid_time", err.Code)
+		}
+
+		assert.Equal([]string{"/time_table/b", "/times/1"}, pointers)
+	}
+
+	type InvalidSchedule struct {
+		Times []string `json:"times" ejson:"time=unix"`
+	}
+
+	assert.PanicsWithValue(`ejson tag option "time" cannot be used with `+
+		`field Times of type []string`, func() {
+		getStructInfo(reflect.TypeOf(InvalidSchedule{}))
+	})
+}
+
+func TestTimePrecision(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `{"date": 1700000000.123456789, "timestamp": 1700000000123}`
+
+	var event TestEvent
+	if assert.NoError(Unmarshal([]byte(data), &event)) {
+		assert.Equal(int64(1700000000123), event.Timestamp.UnixMilli())
+
+		data2, err := json.Marshal(event.Timestamp)
+		if assert.NoError(err) {
+			assert.Equal("1700000000123", string(data2))
+		}
+	}
+
+	var value interface{}
+	if err := Unmarshal([]byte(data), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	event = TestEvent{}
+	if assert.NoError(DecodeValue(value, &event)) {
+		assert.Equal(int64(1700000000123), event.Timestamp.UnixMilli())
+	}
+
+	// Numbers decoded as json.Number keep all their digits
+	ts, err := parseTime(json.Number("1700000000.123456789"),
+		[]TimeFormat{TimeFormatUnix})
+	if assert.NoError(err) {
+		assert.Equal(time.Unix(1700000000, 123456789).UTC(), ts)
+	}
+
+	ts, err = parseTime(json.Number("-1500.25"),
+		[]TimeFormat{TimeFormatUnixMilli})
+	if assert.NoError(err) {
+		assert.Equal(time.UnixMicro(-1500250).UTC(), ts)
+	}
+
+	ts, err = parseTime(json.Number("1.7e12"),
+		[]TimeFormat{TimeFormatUnixMilli})
+	if assert.NoError(err) {
+		assert.Equal(time.UnixMilli(1700000000000).UTC(), ts)
+	}
+
+	_, err = parseTime(1e300, []TimeFormat{TimeFormatUnixMilli})
+	assert.Error(err)
+
+	assertUnix := func(expected string, ts time.Time) {
+		t.Helper()
+
+		data, err := json.Marshal(NewTime(ts, TimeFormatUnix))
+		if assert.NoError(err) {
+			assert.Equal(expected, string(data))
+		}
+	}
+
+	assertUnix("1700000000", time.Unix(1700000000, 0))
+	assertUnix("1700000000.123456789", time.Unix(1700000000, 123456789))
+	assertUnix("1700000000.5", time.Unix(1700000000, 500000000))
+	assertUnix("-1.5", time.Unix(-2, 500000000))
+	assertUnix("-0.000000001", time.Unix(0, -1))
+}
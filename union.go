@@ -0,0 +1,167 @@
+package ejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// UnionDecoder decodes objects whose type is identified by the value of a
+// discriminator member. It is mostly useful in UnmarshalJSON methods; note
+// that in that case, pointers in validation errors are relative to the value
+// being decoded by the method.
+type UnionDecoder struct {
+	Member string
+
+	types map[string]reflect.Type
+}
+
+func NewUnionDecoder(member string) *UnionDecoder {
+	return &UnionDecoder{
+		Member: member,
+
+		types: make(map[string]reflect.Type),
+	}
+}
+
+// Register associates a discriminator value with the type of a prototype
+// value, e.g. (*Circle)(nil). Decoded values have the same type as the
+// prototype.
+func (d *UnionDecoder) Register(value string, prototype interface{}) {
+	t := reflect.TypeOf(prototype)
+	if t == nil {
+		panic(fmt.Sprintf("invalid nil prototype for discriminator %q",
+			value))
+	}
+
+	if _, found := d.types[value]; found {
+		panic(fmt.Sprintf("duplicate discriminator %q", value))
+	}
+
+	d.types[value] = t
+}
+
+func (d *UnionDecoder) Decode(data []byte) (interface{}, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, ConvertUnmarshallingError(err)
+	}
+
+	if members == nil {
+		return nil, ValidationErrors{
+			&ValidationError{
+				Code:    "missing_or_null_value",
+				Message: "missing or null value",
+			},
+		}
+	}
+
+	discriminatorData, found := members[d.Member]
+	if !found {
+		return nil, ValidationErrors{
+			&ValidationError{
+				Pointer: NewPointer(d.Member),
+				Code:    "missing_discriminator",
+				Message: fmt.Sprintf("missing member %q", d.Member),
+			},
+		}
+	}
+
+	var discriminator string
+	if err := json.Unmarshal(discriminatorData, &discriminator); err != nil {
+		return nil, ValidationErrors{
+			&ValidationError{
+				Pointer: NewPointer(d.Member),
+				Code:    "invalid_value_type",
+				Message: "discriminator must be a string",
+			},
+		}
+	}
+
+	t, found := d.types[discriminator]
+	if !found {
+		return nil, ValidationErrors{
+			&ValidationError{
+				Pointer: NewPointer(d.Member),
+				Code:    "unknown_discriminator",
+				Message: d.unknownDiscriminatorMessage(),
+			},
+		}
+	}
+
+	var ptr reflect.Value
+	if t.Kind() == reflect.Pointer {
+		ptr = reflect.New(t.Elem())
+	} else {
+		ptr = reflect.New(t)
+	}
+
+	if err := unmarshal(data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return ptr.Interface(), nil
+	}
+
+	return ptr.Elem().Interface(), nil
+}
+
+func (d *UnionDecoder) DecodeArray(data []byte) ([]interface{}, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, ConvertUnmarshallingError(err)
+	}
+
+	values := make([]interface{}, len(elements))
+	var errs ValidationErrors
+
+	for i, element := range elements {
+		value, err := d.Decode(element)
+		if err != nil {
+			elementErrs, ok := err.(ValidationErrors)
+			if !ok {
+				return nil, err
+			}
+
+			elementErrs.prepend(NewPointer(i))
+			errs = append(errs, elementErrs...)
+
+			continue
+		}
+
+		values[i] = value
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return values, nil
+}
+
+func (d *UnionDecoder) unknownDiscriminatorMessage() string {
+	values := make([]string, 0, len(d.types))
+	for value := range d.types {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "member %q must be one of the following strings: ",
+		d.Member)
+
+	for i, value := range values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(value)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,53 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestCircle struct {
+	Radius int `json:"radius"`
+}
+
+type TestSquare struct {
+	Side int `json:"side"`
+}
+
+func (c *TestCircle) ValidateJSON(v *Validator) {
+	v.CheckIntMin("radius", c.Radius, 1)
+}
+
+func TestUnionDecoder(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewUnionDecoder("type")
+	d.Register("circle", (*TestCircle)(nil))
+	d.Register("square", TestSquare{})
+
+	var validationErrs ValidationErrors
+
+	values, err := d.DecodeArray([]byte(`[{"type": "circle", "radius": 2},
+{"type": "square", "side": 3}]`))
+	if assert.NoError(err) {
+		assert.Equal([]interface{}{
+			&TestCircle{Radius: 2},
+			TestSquare{Side: 3},
+		}, values)
+	}
+
+	_, err = d.DecodeArray([]byte(`[{"type": "circle", "radius": 1},
+{"type": "triangle"}, {"radius": 2}, {"type": "circle", "radius": 0}]`))
+	if assert.ErrorAs(err, &validationErrs) {
+		if assert.Equal(3, len(validationErrs)) {
+			assert.Equal("/1/type", validationErrs[0].Pointer.String())
+			assert.Equal("unknown_discriminator", validationErrs[0].Code)
+
+			assert.Equal("/2/type", validationErrs[1].Pointer.String())
+			assert.Equal("missing_discriminator", validationErrs[1].Code)
+
+			assert.Equal("/3/radius", validationErrs[2].Pointer.String())
+			assert.Equal("integer_too_small", validationErrs[2].Code)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package ejson
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var validatableType = reflect.TypeOf((*Validatable)(nil)).Elem()
+
+// ValidateDeep validates a value as Validate does, then traverses structure
+// fields, array and slice elements and map values, and validates all
+// reachable values implementing Validatable which were not already validated
+// by the ValidateJSON method of a parent value, e.g. with CheckObject.
+// Errors are reported with the pointer of the value in the JSON
+// representation of the top-level value. The result is reported to the
+// metrics recorder if there is one.
+func ValidateDeep(value interface{}) error {
+	v := NewValidator()
+	v.validated = make(map[interface{}]struct{})
+
+	v.validateDeep(reflect.ValueOf(value), make(map[interface{}]struct{}))
+
+	err := v.Error()
+	recordValidation(err)
+	return err
+}
+
+func (v *Validator) validateDeep(rv reflect.Value, traversed map[interface{}]struct{}) {
+	if !rv.IsValid() {
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !rv.IsNil() {
+			v.validateDeep(rv.Elem(), traversed)
+		}
+
+		return
+
+	case reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return
+		}
+	}
+
+	if !rv.CanInterface() {
+		return
+	}
+
+	// Map values and values passed directly are not addressable; we copy
+	// them so that ValidateJSON methods with a pointer receiver are called.
+	if !rv.CanAddr() {
+		rv2 := reflect.New(rv.Type()).Elem()
+		rv2.Set(rv)
+		rv = rv2
+	}
+
+	// Values are identified by their address, so that we can detect them
+	// when they were validated with CheckObject and avoid infinite loops with
+	// circular data structures.
+	key := rv.Addr().Interface()
+
+	if _, found := traversed[key]; found {
+		return
+	}
+
+	traversed[key] = struct{}{}
+
+	if validatable, ok := key.(Validatable); ok {
+		if _, validated := v.validated[key]; !validated {
+			v.validated[key] = struct{}{}
+			validatable.ValidateJSON(v)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		info := getStructInfo(rv.Type())
+
+		for _, field := range info.Fields {
+			fieldValue := fieldByIndex(rv, field.Index)
+			if !fieldValue.IsValid() {
+				continue
+			}
+
+			v.WithChild(field.Name, func() {
+				v.validateDeep(fieldValue, traversed)
+			})
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			v.WithChild(i, func() {
+				v.validateDeep(rv.Index(i), traversed)
+			})
+		}
+
+	case reflect.Map:
+		values := make(map[string]reflect.Value, rv.Len())
+
+		iter := rv.MapRange()
+		for iter.Next() {
+			switch key := iter.Key(); key.Kind() {
+			case reflect.String:
+				values[key.String()] = iter.Value()
+
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+				reflect.Int64:
+				values[strconv.FormatInt(key.Int(), 10)] = iter.Value()
+
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+				reflect.Uint64, reflect.Uintptr:
+				values[strconv.FormatUint(key.Uint(), 10)] = iter.Value()
+			}
+		}
+
+		tokens := make([]string, 0, len(values))
+		for token := range values {
+			tokens = append(tokens, token)
+		}
+
+		sort.Strings(tokens)
+
+		for _, token := range tokens {
+			v.WithChild(token, func() {
+				v.validateDeep(values[token], traversed)
+			})
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestDeepRoot struct {
+	Name     string                   `json:"name"`
+	Bar      *TestBar                 `json:"bar"`
+	Bars     []TestBar                `json:"bars"`
+	BarTable map[string]*TestBar      `json:"bar_table"`
+	Nodes    map[int]*TestDeepNode    `json:"nodes"`
+	Any      interface{}              `json:"any"`
+	Checked  *TestBar                 `json:"checked"`
+	Foos     []*TestFoo               `json:"foos,omitempty"`
+	Extra    map[string]*TestDeepRoot `json:"-"`
+}
+
+type TestDeepNode struct {
+	Value int           `json:"value"`
+	Next  *TestDeepNode `json:"next"`
+}
+
+func (r *TestDeepRoot) ValidateJSON(v *Validator) {
+	v.CheckStringNotEmpty("name", r.Name)
+	v.CheckOptionalObject("checked", r.Checked)
+}
+
+func (n *TestDeepNode) ValidateJSON(v *Validator) {
+	v.CheckIntMax("value", n.Value, 10)
+}
+
+func TestValidateDeep(t *testing.T) {
+	assert := assert.New(t)
+
+	node1 := &TestDeepNode{Value: 1}
+	node2 := &TestDeepNode{Value: 20, Next: node1}
+	node1.Next = node2
+
+	root := TestDeepRoot{
+		Bar:  &TestBar{Integers: []int{1, 20}},
+		Bars: []TestBar{{Integers: []int{30}}, {}},
+		BarTable: map[string]*TestBar{
+			"b": {Integers: []int{40}},
+			"a": {Integers: []int{50}},
+			"c": nil,
+		},
+		Nodes:   map[int]*TestDeepNode{1: node1, 2: node2},
+		Any:     &TestBar{Integers: []int{60}},
+		Checked: &TestBar{Integers: []int{70}},
+	}
+
+	assert.NoError(Validate(&TestBar{}))
+
+	err := ValidateDeep(&root)
+
+	var validationErrs ValidationErrors
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+		}
+
+		assert.Equal([]string{
+			"/name",
+			"/checked/Integers/0",
+			"/bar/Integers/1",
+			"/bars/0/Integers/0",
+			"/bar_table/a/Integers/0",
+			"/bar_table/b/Integers/0",
+			"/nodes/1/next/value",
+			"/any/Integers/0",
+		}, pointers)
+	}
+
+	// Values which are not addressable
+	err = ValidateDeep(map[string]interface{}{
+		"m": map[string]TestBar{"a": {Integers: []int{1, 20}}},
+		"b": TestBar{Integers: []int{30}},
+	})
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+		}
+
+		assert.Equal([]string{"/b/Integers/0", "/m/a/Integers/1"}, pointers)
+	}
+
+	assert.Error(ValidateDeep(TestBar{Integers: []int{40}}))
+
+	assert.NoError(ValidateDeep(&TestDeepRoot{Name: "a"}))
+	assert.NoError(ValidateDeep(nil))
+}
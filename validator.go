@@ -10,9 +10,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
 	"unicode/utf8"
 
 	"go.n16f.net/uuid"
+	"golang.org/x/text/unicode/norm"
 )
 
 type ValidationError struct {
@@ -24,10 +27,28 @@ type ValidationError struct {
 type ValidationErrors []*ValidationError
 
 type Validator struct {
-	Pointer Pointer
-	Errors  ValidationErrors
+	Errors ValidationErrors
+
+	// The tokens of the current pointer, modified in place when tokens are
+	// pushed and popped. Pointers are only built when errors are added.
+	path []validatorToken
+
+	// The value used to iterate over map keys in CheckObjectMap
+	mapKey reflect.Value
+
+	// Objects already validated, only tracked by ValidateDeep
+	validated map[interface{}]struct{}
+}
+
+// A token of the current pointer of a validator. Array indexes are stored as
+// integers so that they are only formatted when an error is added.
+type validatorToken struct {
+	s     string
+	index int // -1 for string tokens
 }
 
+var stringSliceType = reflect.TypeOf([]string{})
+
 type Validatable interface {
 	ValidateJSON(v *Validator)
 }
@@ -57,22 +78,63 @@ func (errs ValidationErrors) Error() string {
 	return buf.String()
 }
 
+func (errs ValidationErrors) prepend(p Pointer) {
+	for _, err := range errs {
+		err.Pointer = p.Child(err.Pointer)
+	}
+}
+
+// Validate validates a value if it implements Validatable. The result is
+// reported to the metrics recorder if there is one.
 func Validate(value interface{}) error {
-	v := NewValidator()
+	err := validate(value)
+	recordValidation(err)
+	return err
+}
 
-	if validatableValue, ok := value.(Validatable); ok {
-		validatableValue.ValidateJSON(v)
+func validate(value interface{}) error {
+	validatableValue, ok := value.(Validatable)
+	if !ok {
+		return nil
 	}
 
-	if len(v.Errors) > 0 {
-		return v.Error()
-	}
+	v := NewValidator()
+	validatableValue.ValidateJSON(v)
 
-	return nil
+	return v.Error()
 }
 
 func NewValidator() *Validator {
-	return &Validator{}
+	return &Validator{
+		path: make([]validatorToken, 0, 8),
+	}
+}
+
+var validatorPool = sync.Pool{
+	New: func() interface{} {
+		return NewValidator()
+	},
+}
+
+// AcquireValidator returns a validator from a shared pool. Validators must
+// be released with ReleaseValidator once they are not used anymore; errors
+// returned by Validator.Error remain valid after the release. Reusing
+// validators this way avoids all allocations when validation succeeds.
+func AcquireValidator() *Validator {
+	return validatorPool.Get().(*Validator)
+}
+
+func ReleaseValidator(v *Validator) {
+	v.Reset()
+	validatorPool.Put(v)
+}
+
+// Reset clears the current pointer and errors of the validator so that it
+// can be reused. The memory used by the pointer is kept.
+func (v *Validator) Reset() {
+	v.path = v.path[:0]
+	v.Errors = nil
+	v.validated = nil
 }
 
 func (v *Validator) Error() error {
@@ -83,23 +145,77 @@ func (v *Validator) Error() error {
 	return v.Errors
 }
 
+// Pointer returns a copy of the current pointer of the validator.
+func (v *Validator) Pointer() Pointer {
+	p := make(Pointer, len(v.path), len(v.path)+1)
+
+	for i, token := range v.path {
+		if token.index >= 0 {
+			p[i] = strconv.Itoa(token.index)
+		} else {
+			p[i] = token.s
+		}
+	}
+
+	return p
+}
+
 func (v *Validator) Push(token interface{}) {
-	v.Pointer = v.Pointer.Child(token)
+	switch t := token.(type) {
+	case string:
+		v.pushString(t)
+
+	case Token:
+		v.pushString(string(t))
+
+	case int:
+		v.pushIndex(t)
+
+	case Pointer:
+		for _, s := range t {
+			v.pushString(s)
+		}
+
+	case nil:
+
+	default:
+		panic(fmt.Sprintf("invalid json pointer token %#v (%T)",
+			token, token))
+	}
+}
+
+func (v *Validator) pushString(s string) {
+	v.path = append(v.path, validatorToken{s: s, index: -1})
+}
+
+func (v *Validator) pushIndex(i int) {
+	v.path = append(v.path, validatorToken{index: i})
 }
 
 func (v *Validator) Pop() {
-	v.Pointer = v.Pointer.Parent()
+	if len(v.path) == 0 {
+		panic("empty pointer")
+	}
+
+	v.path = v.path[:len(v.path)-1]
 }
 
 func (v *Validator) WithChild(token interface{}, fn func()) {
+	// Tokens can be nil or pointers, so we cannot just pop a single token
+	n := len(v.path)
+
 	v.Push(token)
-	defer v.Pop()
+	defer v.truncate(n)
 
 	fn()
 }
 
+func (v *Validator) truncate(n int) {
+	v.path = v.path[:n]
+}
+
 func (v *Validator) AddError(token interface{}, code, format string, args ...interface{}) {
-	pointer := v.Pointer.Child(token)
+	pointer := v.Pointer().appendToken(token)
 
 	err := ValidationError{
 		Pointer: pointer,
@@ -118,14 +234,79 @@ func (v *Validator) Check(token interface{}, value bool, code, format string, ar
 	return value
 }
 
+// Checkpoint records the state of a validator so that errors added after it
+// can be discarded, e.g. to check that a value matches at least one of
+// several alternatives.
+type Checkpoint struct {
+	v          *Validator
+	nbErrors   int
+	pointerLen int
+}
+
+func (v *Validator) Checkpoint() Checkpoint {
+	return Checkpoint{
+		v:          v,
+		nbErrors:   len(v.Errors),
+		pointerLen: len(v.path),
+	}
+}
+
+// Errors returns the errors added since the checkpoint was created.
+func (c Checkpoint) Errors() ValidationErrors {
+	if len(c.v.Errors) == c.nbErrors {
+		return nil
+	}
+
+	return append(ValidationErrors(nil), c.v.Errors[c.nbErrors:]...)
+}
+
+// Rollback discards all errors added since the checkpoint was created and
+// restores the current pointer.
+func (c Checkpoint) Rollback() {
+	for i := c.nbErrors; i < len(c.v.Errors); i++ {
+		c.v.Errors[i] = nil
+	}
+
+	c.v.Errors = c.v.Errors[:c.nbErrors]
+	c.v.truncate(c.pointerLen)
+}
+
+// Commit keeps all errors added since the checkpoint was created. It does not
+// modify the validator and only exists to make the intent explicit.
+func (c Checkpoint) Commit() {
+}
+
+// Try calls fn and returns the errors it added to the validator. These errors
+// are removed from the validator.
+func (v *Validator) Try(fn func()) ValidationErrors {
+	c := v.Checkpoint()
+
+	fn()
+
+	errs := c.Errors()
+	c.Rollback()
+
+	return errs
+}
+
 func (v *Validator) CheckIntMin(token interface{}, i int, min int) bool {
-	return v.Check(token, i >= min, "integer_too_small",
+	if i >= min {
+		return true
+	}
+
+	v.AddError(token, "integer_too_small",
 		"integer must be greater or equal to %d", min)
+	return false
 }
 
 func (v *Validator) CheckIntMax(token interface{}, i int, max int) bool {
-	return v.Check(token, i <= max, "integer_too_large",
+	if i <= max {
+		return true
+	}
+
+	v.AddError(token, "integer_too_large",
 		"integer must be lower or equal to %d", max)
+	return false
 }
 
 func (v *Validator) CheckIntMinMax(token interface{}, i int, min, max int) bool {
@@ -137,13 +318,23 @@ func (v *Validator) CheckIntMinMax(token interface{}, i int, min, max int) bool
 }
 
 func (v *Validator) CheckInt64Min(token interface{}, i, min int64) bool {
-	return v.Check(token, i >= min, "integer_too_small",
+	if i >= min {
+		return true
+	}
+
+	v.AddError(token, "integer_too_small",
 		"integer must be greater or equal to %d", min)
+	return false
 }
 
 func (v *Validator) CheckInt64Max(token interface{}, i, max int64) bool {
-	return v.Check(token, i <= max, "integer_too_large",
+	if i <= max {
+		return true
+	}
+
+	v.AddError(token, "integer_too_large",
 		"integer must be lower or equal to %d", max)
+	return false
 }
 
 func (v *Validator) CheckInt64MinMax(token interface{}, i, min, max int64) bool {
@@ -155,13 +346,23 @@ func (v *Validator) CheckInt64MinMax(token interface{}, i, min, max int64) bool
 }
 
 func (v *Validator) CheckFloatMin(token interface{}, i, min float64) bool {
-	return v.Check(token, i >= min, "float_too_small",
+	if i >= min {
+		return true
+	}
+
+	v.AddError(token, "float_too_small",
 		"float %f must be greater or equal to %f", i, min)
+	return false
 }
 
 func (v *Validator) CheckFloatMax(token interface{}, i, max float64) bool {
-	return v.Check(token, i <= max, "float_too_large",
+	if i <= max {
+		return true
+	}
+
+	v.AddError(token, "float_too_large",
 		"float %f must be lower or equal to %f", i, max)
+	return false
 }
 
 func (v *Validator) CheckFloatMinMax(token interface{}, i, min, max float64) bool {
@@ -174,14 +375,24 @@ func (v *Validator) CheckFloatMinMax(token interface{}, i, min, max float64) boo
 
 func (v *Validator) CheckStringLengthMin(token interface{}, s string, min int) bool {
 	length := utf8.RuneCountInString(s)
-	return v.Check(token, length >= min, "string_too_short",
+	if length >= min {
+		return true
+	}
+
+	v.AddError(token, "string_too_short",
 		"string length must be greater or equal to %d", min)
+	return false
 }
 
 func (v *Validator) CheckStringLengthMax(token interface{}, s string, max int) bool {
 	length := utf8.RuneCountInString(s)
-	return v.Check(token, length <= max, "string_too_long",
+	if length <= max {
+		return true
+	}
+
+	v.AddError(token, "string_too_long",
 		"string length must be lower or equal to %d", max)
+	return false
 }
 
 func (v *Validator) CheckStringLengthMinMax(token interface{}, s string, min, max int) bool {
@@ -198,50 +409,60 @@ func (v *Validator) CheckStringNotEmpty(token interface{}, s string) bool {
 }
 
 func (v *Validator) CheckStringValue(token interface{}, value interface{}, values interface{}) bool {
-	valueType := reflect.TypeOf(value)
-	if valueType.Kind() != reflect.String {
-		panic(fmt.Sprintf("value %#v (%T) is not a string", value, value))
+	s, ok := value.(string)
+	if !ok {
+		valueType := reflect.TypeOf(value)
+		if valueType == nil || valueType.Kind() != reflect.String {
+			panic(fmt.Sprintf("value of type %v is not a string", valueType))
+		}
+
+		s = reflect.ValueOf(value).String()
 	}
 
-	s := reflect.ValueOf(value).String()
+	// Avoid reflection for the most common case
+	if ss, ok := values.([]string); ok {
+		for _, s2 := range ss {
+			if s == s2 {
+				return true
+			}
+		}
+	}
 
 	valuesType := reflect.TypeOf(values)
 	if valuesType.Kind() != reflect.Slice {
-		panic(fmt.Sprintf("values %#v (%T) are not a slice", values, values))
+		panic(fmt.Sprintf("values of type %v are not a slice", valuesType))
 	}
 	if valuesType.Elem().Kind() != reflect.String {
-		panic(fmt.Sprintf("values %#v (%T) are not a slice of strings",
-			values, values))
+		panic(fmt.Sprintf("values of type %v are not a slice of strings",
+			valuesType))
 	}
 
 	valuesValue := reflect.ValueOf(values)
 
-	found := false
-	for i := 0; i < valuesValue.Len(); i++ {
-		s2 := valuesValue.Index(i).String()
-		if s == s2 {
-			found = true
+	if valuesType != stringSliceType {
+		for i := 0; i < valuesValue.Len(); i++ {
+			if s == valuesValue.Index(i).String() {
+				return true
+			}
 		}
 	}
 
-	if !found {
-		var buf bytes.Buffer
+	var buf bytes.Buffer
 
-		buf.WriteString("value must be one of the following strings: ")
+	buf.WriteString("value must be one of the following strings: ")
 
-		for i := 0; i < valuesValue.Len(); i++ {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-
-			s2 := valuesValue.Index(i).String()
-			buf.WriteString(s2)
+	for i := 0; i < valuesValue.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(", ")
 		}
 
-		v.AddError(token, "invalid_value", "%s", buf.String())
+		s2 := valuesValue.Index(i).String()
+		buf.WriteString(s2)
 	}
 
-	return found
+	v.AddError(token, "invalid_value", "%s", buf.String())
+
+	return false
 }
 
 func (v *Validator) CheckStringMatch(token interface{}, s string, re *regexp.Regexp) bool {
@@ -259,6 +480,64 @@ func (v *Validator) CheckStringMatch2(token interface{}, s string, re *regexp.Re
 	return true
 }
 
+func (v *Validator) CheckStringLowercase(token interface{}, s string) bool {
+	if strings.IndexFunc(s, isUpperOrTitle) < 0 {
+		return true
+	}
+
+	v.AddError(token, "string_not_lowercase", "string must be lowercase")
+	return false
+}
+
+func (v *Validator) CheckStringUppercase(token interface{}, s string) bool {
+	if strings.IndexFunc(s, isLowerOrTitle) < 0 {
+		return true
+	}
+
+	v.AddError(token, "string_not_uppercase", "string must be uppercase")
+	return false
+}
+
+func isUpperOrTitle(c rune) bool {
+	return unicode.IsUpper(c) || unicode.IsTitle(c)
+}
+
+func isLowerOrTitle(c rune) bool {
+	return unicode.IsLower(c) || unicode.IsTitle(c)
+}
+
+// CheckStringNormalized checks that a string is in a specific Unicode
+// normalization form.
+func (v *Validator) CheckStringNormalized(token interface{}, s string, form norm.Form) bool {
+	if form.IsNormalString(s) {
+		return true
+	}
+
+	v.AddError(token, "string_not_normalized",
+		"string must be in Unicode normalization form %s",
+		normalizationFormName(form))
+	return false
+}
+
+func (v *Validator) CheckStringNFC(token interface{}, s string) bool {
+	return v.CheckStringNormalized(token, s, norm.NFC)
+}
+
+func normalizationFormName(form norm.Form) string {
+	switch form {
+	case norm.NFC:
+		return "NFC"
+	case norm.NFD:
+		return "NFD"
+	case norm.NFKC:
+		return "NFKC"
+	case norm.NFKD:
+		return "NFKD"
+	default:
+		panic(fmt.Sprintf("unknown normalization form %v", form))
+	}
+}
+
 func (v *Validator) CheckStringURI(token interface{}, s string) bool {
 	// The url.Parse function parses URI references. Most of the time we are
 	// interested in URIs, so we check that there is a schema.
@@ -277,6 +556,70 @@ func (v *Validator) CheckStringURI(token interface{}, s string) bool {
 	return true
 }
 
+// URLOptions are the additional requirements checked by CheckStringURL.
+type URLOptions struct {
+	// The list of allowed schemes, compared case-insensitively. If the list
+	// is empty, all schemes are allowed.
+	Schemes []string
+
+	RequireHost    bool
+	ForbidUserinfo bool
+	ForbidFragment bool
+}
+
+// CheckStringURL checks that a string is a URI as CheckStringURI does, then
+// checks each requirement of the options.
+func (v *Validator) CheckStringURL(token interface{}, s string, opts URLOptions) bool {
+	uri, err := url.Parse(s)
+	if err != nil {
+		v.AddError(token, "invalid_uri_format", "string must be a valid uri")
+		return false
+	}
+
+	if uri.Scheme == "" {
+		v.AddError(token, "missing_uri_scheme", "uri must have a scheme")
+		return false
+	}
+
+	if len(opts.Schemes) > 0 {
+		allowed := false
+		for _, scheme := range opts.Schemes {
+			if strings.EqualFold(uri.Scheme, scheme) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			v.AddError(token, "forbidden_uri_scheme",
+				"uri scheme must be one of the following values: %s",
+				strings.Join(opts.Schemes, ", "))
+			return false
+		}
+	}
+
+	if opts.RequireHost && uri.Host == "" {
+		v.AddError(token, "missing_uri_host", "uri must have a host")
+		return false
+	}
+
+	if opts.ForbidUserinfo && uri.User != nil {
+		v.AddError(token, "forbidden_uri_userinfo",
+			"uri must not contain user information")
+		return false
+	}
+
+	// The fragment of the parsed URI is empty both when there is no fragment
+	// and when the fragment is empty.
+	if opts.ForbidFragment && strings.IndexByte(s, '#') >= 0 {
+		v.AddError(token, "forbidden_uri_fragment",
+			"uri must not contain a fragment")
+		return false
+	}
+
+	return true
+}
+
 func (v *Validator) CheckUUID(token interface{}, value interface{}) bool {
 	var id uuid.UUID
 
@@ -332,6 +675,46 @@ func (v *Validator) CheckNetworkAddress(token any, s string) {
 	}
 }
 
+func (v *Validator) CheckDNSLabel(token any, s string) bool {
+	// RFC 1123 2.1. Host Names and Numbers
+
+	const maxLabelLength = 63
+
+	if !v.CheckStringNotEmpty(token, s) {
+		return false
+	}
+
+	if len(s) > maxLabelLength {
+		v.AddError(token, "dns_label_too_long",
+			"dns label must be %d character long at most", maxLabelLength)
+		return false
+	}
+
+	isLetterOrDigit := func(c byte) bool {
+		return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' ||
+			c >= '0' && c <= '9'
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if (i == 0 || i == len(s)-1) && !isLetterOrDigit(c) {
+			v.AddError(token, "invalid_dns_label",
+				"dns label must start and end with a letter or digit")
+			return false
+		}
+
+		if !isLetterOrDigit(c) && c != '-' {
+			v.AddError(token, "invalid_dns_label",
+				"dns label must only contain letters, digits and '-' "+
+					"characters")
+			return false
+		}
+	}
+
+	return true
+}
+
 func (v *Validator) CheckDomainName(token any, s string) {
 	addError := func(format string, args ...any) {
 		v.AddError(token, "invalid_domain_name", format, args...)
@@ -436,8 +819,13 @@ func (v *Validator) CheckArrayLengthMin(token interface{}, value interface{}, mi
 
 	checkArray(value, &length)
 
-	return v.Check(token, length >= min, "array_too_small",
+	if length >= min {
+		return true
+	}
+
+	v.AddError(token, "array_too_small",
 		"array must contain %d or more elements", min)
+	return false
 }
 
 func (v *Validator) CheckArrayLengthMax(token interface{}, value interface{}, max int) bool {
@@ -445,8 +833,13 @@ func (v *Validator) CheckArrayLengthMax(token interface{}, value interface{}, ma
 
 	checkArray(value, &length)
 
-	return v.Check(token, length <= max, "array_too_large",
+	if length <= max {
+		return true
+	}
+
+	v.AddError(token, "array_too_large",
 		"array must contain %d or less elements", max)
+	return false
 }
 
 func (v *Validator) CheckArrayLengthMinMax(token interface{}, value interface{}, min, max int) bool {
@@ -498,55 +891,63 @@ func (v *Validator) CheckObject(token interface{}, value interface{}) bool {
 }
 
 func (v *Validator) CheckObjectArray(token interface{}, value interface{}) bool {
-	valueType := reflect.TypeOf(value)
-	kind := valueType.Kind()
-
-	if kind != reflect.Array && kind != reflect.Slice {
-		panic(fmt.Sprintf("value %#v (%T) is not an array or slice",
-			value, value))
+	if !objectArrayType(reflect.TypeOf(value)) {
+		return true
 	}
 
 	ok := true
 
-	v.WithChild(token, func() {
-		values := reflect.ValueOf(value)
+	n := len(v.path)
+	v.Push(token)
+
+	values := reflect.ValueOf(value)
 
-		for i := 0; i < values.Len(); i++ {
-			child := values.Index(i).Interface()
-			childOk := v.CheckObject(strconv.Itoa(i), child)
-			ok = ok && childOk
-		}
-	})
+	for i := 0; i < values.Len(); i++ {
+		child := values.Index(i).Interface()
+
+		v.pushIndex(i)
+		childOk := v.CheckObject(nil, child)
+		v.Pop()
+
+		ok = ok && childOk
+	}
+
+	v.truncate(n)
 
 	return ok
 }
 
 func (v *Validator) CheckObjectMap(token interface{}, value interface{}) bool {
 	valueType := reflect.TypeOf(value)
-	if valueType.Kind() != reflect.Map {
-		panic(fmt.Sprintf("value %#v (%T) is not a map", value, value))
+	if !objectMapType(valueType) {
+		return true
 	}
 
 	ok := true
 
-	v.WithChild(token, func() {
-		values := reflect.ValueOf(value)
+	n := len(v.path)
+	v.Push(token)
 
-		iter := values.MapRange()
-		for iter.Next() {
-			key := iter.Key()
-			if key.Kind() != reflect.String {
-				panic(fmt.Sprintf("value %#v (%T) is a map whose keys are "+
-					"not strings", value, value))
-			}
-			keyString := key.Interface().(string)
+	// MapIter.Key allocates a new value for each key. The key value is kept
+	// across calls: it is only used before validating each map value.
+	if !v.mapKey.IsValid() || v.mapKey.Type() != valueType.Key() {
+		v.mapKey = reflect.New(valueType.Key()).Elem()
+	}
+	key := v.mapKey
 
-			value := iter.Value().Interface()
+	iter := reflect.ValueOf(value).MapRange()
+	for iter.Next() {
+		key.SetIterKey(iter)
+		value := iter.Value().Interface()
 
-			valueOk := v.CheckObject(keyString, value)
-			ok = ok && valueOk
-		}
-	})
+		v.pushString(key.String())
+		valueOk := v.CheckObject(nil, value)
+		v.Pop()
+
+		ok = ok && valueOk
+	}
+
+	v.truncate(n)
 
 	return ok
 }
@@ -559,28 +960,92 @@ func (v *Validator) doCheckObject(token interface{}, value interface{}) bool {
 		return true
 	}
 
+	if v.validated != nil {
+		v.validated[value] = struct{}{}
+	}
+
+	n := len(v.path)
+
 	v.Push(token)
 	value2.ValidateJSON(v)
-	v.Pop()
+	v.truncate(n)
 
 	return len(v.Errors) == nbErrors
 }
 
+// Types already checked by CheckObject, CheckObjectArray and CheckObjectMap.
+// Array and map types are associated with a boolean indicating whether their
+// elements can implement Validatable; if they cannot, they are not iterated
+// over.
+var (
+	objectTypes      sync.Map // reflect.Type -> struct{}
+	objectArrayTypes sync.Map // reflect.Type -> bool
+	objectMapTypes   sync.Map // reflect.Type -> bool
+)
+
 func checkObject(value interface{}) bool {
 	valueType := reflect.TypeOf(value)
 	if valueType == nil {
 		return false
 	}
 
-	if valueType.Kind() != reflect.Pointer {
-		panic(fmt.Sprintf("value %#v (%T) is not a pointer", value, value))
-	}
+	if _, found := objectTypes.Load(valueType); !found {
+		if valueType.Kind() != reflect.Pointer {
+			panic(fmt.Sprintf("value of type %v is not a pointer", valueType))
+		}
 
-	pointedValueType := valueType.Elem()
-	if pointedValueType.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("value %#v (%T) is not a pointer to a structure",
-			value, value))
+		if valueType.Elem().Kind() != reflect.Struct {
+			panic(fmt.Sprintf("value of type %v is not a pointer to a "+
+				"structure", valueType))
+		}
+
+		objectTypes.Store(valueType, struct{}{})
 	}
 
 	return !reflect.ValueOf(value).IsZero()
 }
+
+func objectArrayType(t reflect.Type) bool {
+	if validatable, found := objectArrayTypes.Load(t); found {
+		return validatable.(bool)
+	}
+
+	if kind := t.Kind(); kind != reflect.Array && kind != reflect.Slice {
+		panic(fmt.Sprintf("value of type %v is not an array or slice", t))
+	}
+
+	validatable := elementMayBeValidatable(t.Elem())
+	objectArrayTypes.Store(t, validatable)
+
+	return validatable
+}
+
+func objectMapType(t reflect.Type) bool {
+	if validatable, found := objectMapTypes.Load(t); found {
+		return validatable.(bool)
+	}
+
+	if t.Kind() != reflect.Map {
+		panic(fmt.Sprintf("value of type %v is not a map", t))
+	}
+
+	if t.Key().Kind() != reflect.String {
+		panic(fmt.Sprintf("value of type %v is a map whose keys are "+
+			"not strings", t))
+	}
+
+	validatable := elementMayBeValidatable(t.Elem())
+	objectMapTypes.Store(t, validatable)
+
+	return validatable
+}
+
+// Element types which are not pointers to structures are not rejected here:
+// CheckObject panics for each element.
+func elementMayBeValidatable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		return t.Implements(validatableType)
+	}
+
+	return true
+}
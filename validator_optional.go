@@ -0,0 +1,149 @@
+package ejson
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Optional check variants operate on pointers to scalar values, as used for
+// optional object members, and succeed if the pointer is nil.
+
+// CheckNotNil checks that a pointer, map, slice or interface value is not
+// nil, typically to enforce the presence of a required member decoded into
+// a pointer.
+func (v *Validator) CheckNotNil(token interface{}, value interface{}) bool {
+	if !isNil(value) {
+		return true
+	}
+
+	v.AddError(token, "missing_value", "missing value")
+	return false
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	default:
+		panic(fmt.Sprintf("value %#v (%T) cannot be nil", value, value))
+	}
+}
+
+func (v *Validator) CheckOptionalIntMin(token interface{}, i *int, min int) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckIntMin(token, *i, min)
+}
+
+func (v *Validator) CheckOptionalIntMax(token interface{}, i *int, max int) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckIntMax(token, *i, max)
+}
+
+func (v *Validator) CheckOptionalIntMinMax(token interface{}, i *int, min, max int) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckIntMinMax(token, *i, min, max)
+}
+
+func (v *Validator) CheckOptionalInt64Min(token interface{}, i *int64, min int64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckInt64Min(token, *i, min)
+}
+
+func (v *Validator) CheckOptionalInt64Max(token interface{}, i *int64, max int64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckInt64Max(token, *i, max)
+}
+
+func (v *Validator) CheckOptionalInt64MinMax(token interface{}, i *int64, min, max int64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckInt64MinMax(token, *i, min, max)
+}
+
+func (v *Validator) CheckOptionalFloatMin(token interface{}, i *float64, min float64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckFloatMin(token, *i, min)
+}
+
+func (v *Validator) CheckOptionalFloatMax(token interface{}, i *float64, max float64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckFloatMax(token, *i, max)
+}
+
+func (v *Validator) CheckOptionalFloatMinMax(token interface{}, i *float64, min, max float64) bool {
+	if i == nil {
+		return true
+	}
+
+	return v.CheckFloatMinMax(token, *i, min, max)
+}
+
+func (v *Validator) CheckOptionalStringLengthMin(token interface{}, s *string, min int) bool {
+	if s == nil {
+		return true
+	}
+
+	return v.CheckStringLengthMin(token, *s, min)
+}
+
+func (v *Validator) CheckOptionalStringLengthMax(token interface{}, s *string, max int) bool {
+	if s == nil {
+		return true
+	}
+
+	return v.CheckStringLengthMax(token, *s, max)
+}
+
+func (v *Validator) CheckOptionalStringLengthMinMax(token interface{}, s *string, min, max int) bool {
+	if s == nil {
+		return true
+	}
+
+	return v.CheckStringLengthMinMax(token, *s, min, max)
+}
+
+func (v *Validator) CheckOptionalStringNotEmpty(token interface{}, s *string) bool {
+	if s == nil {
+		return true
+	}
+
+	return v.CheckStringNotEmpty(token, *s)
+}
+
+func (v *Validator) CheckOptionalStringMatch(token interface{}, s *string, re *regexp.Regexp) bool {
+	if s == nil {
+		return true
+	}
+
+	return v.CheckStringMatch(token, *s, re)
+}
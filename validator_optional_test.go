@@ -0,0 +1,73 @@
+package ejson
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOptional(t *testing.T) {
+	assert := assert.New(t)
+
+	i := 5
+	i64 := int64(5)
+	f := 0.5
+	s := "abc"
+	empty := ""
+
+	v := NewValidator()
+
+	assert.True(v.CheckOptionalIntMinMax("a", nil, 1, 3))
+	assert.True(v.CheckOptionalInt64Min("a", nil, 10))
+	assert.True(v.CheckOptionalFloatMax("a", nil, 0.0))
+	assert.True(v.CheckOptionalStringLengthMin("a", nil, 10))
+	assert.True(v.CheckOptionalStringNotEmpty("a", nil))
+	assert.True(v.CheckOptionalStringMatch("a", nil, regexp.MustCompile("^x")))
+	assert.Equal(0, len(v.Errors))
+
+	assert.True(v.CheckOptionalIntMinMax("a", &i, 1, 10))
+	assert.False(v.CheckOptionalIntMinMax("b", &i, 1, 3))
+	assert.False(v.CheckOptionalInt64Min("c", &i64, 10))
+	assert.False(v.CheckOptionalFloatMax("d", &f, 0.0))
+	assert.False(v.CheckOptionalStringLengthMinMax("e", &s, 4, 10))
+	assert.False(v.CheckOptionalStringNotEmpty("f", &empty))
+	assert.False(v.CheckOptionalStringMatch("g", &s,
+		regexp.MustCompile("^x")))
+
+	var pointers, codes []string
+	for _, err := range v.Errors {
+		pointers = append(pointers, err.Pointer.String())
+		codes = append(codes, err.Code)
+	}
+
+	assert.Equal([]string{"/b", "/c", "/d", "/e", "/f", "/g"}, pointers)
+	assert.Equal([]string{"integer_too_large", "integer_too_small",
+		"float_too_large", "string_too_short", "missing_or_empty_string",
+		"invalid_string_format"}, codes)
+}
+
+func TestValidateNotNil(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilString *string
+	var nilMap map[string]int
+	var nilSlice []int
+	s := "abc"
+
+	v := NewValidator()
+
+	assert.True(v.CheckNotNil("a", &s))
+	assert.True(v.CheckNotNil("a", []int{}))
+	assert.False(v.CheckNotNil("b", nilString))
+	assert.False(v.CheckNotNil("c", nilMap))
+	assert.False(v.CheckNotNil("d", nilSlice))
+	assert.False(v.CheckNotNil("e", nil))
+
+	if assert.Equal(4, len(v.Errors)) {
+		assert.Equal("/b", v.Errors[0].Pointer.String())
+		assert.Equal("missing_value", v.Errors[0].Code)
+	}
+
+	assert.Panics(func() { v.CheckNotNil("f", s) })
+}
@@ -1,9 +1,11 @@
 package ejson
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
 )
 
 type TestFoo struct {
@@ -183,15 +185,15 @@ func TestValidate(t *testing.T) {
 
 	// Invalid nested member type
 	//
-	// The standard JSON parser returns the error on the array, nothing we can
-	// do about it.
+	// Recent versions of encoding/json include array indexes in the field
+	// path of type errors.
 	err = Unmarshal([]byte(`{"String": "abcd", "Bars": [{"Integers": true}]}`),
 		&data)
 
 	if assert.ErrorAs(err, &validationErrs) {
 		if assert.Equal(1, len(validationErrs)) {
 			validationErr = validationErrs[0]
-			assert.Equal("/Bars/Integers", validationErr.Pointer.String())
+			assert.Equal("/Bars/0/Integers", validationErr.Pointer.String())
 			assert.Equal("invalid_value_type", validationErr.Code)
 		}
 	}
@@ -247,3 +249,366 @@ func TestValidateDNSLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateStringCase(t *testing.T) {
+	assert := assert.New(t)
+
+	v := NewValidator()
+
+	assert.True(v.CheckStringLowercase("a", "abc-123 été"))
+	assert.True(v.CheckStringLowercase("a", ""))
+	assert.False(v.CheckStringLowercase("a", "abC"))
+	assert.False(v.CheckStringLowercase("a", "ǅ"))
+
+	assert.True(v.CheckStringUppercase("b", "ABC-123 ÉTÉ"))
+	assert.False(v.CheckStringUppercase("b", "ABc"))
+
+	var codes []string
+	for _, err := range v.Errors {
+		codes = append(codes, err.Code)
+	}
+
+	assert.Equal([]string{"string_not_lowercase", "string_not_lowercase",
+		"string_not_uppercase"}, codes)
+}
+
+func TestValidateStringNormalized(t *testing.T) {
+	assert := assert.New(t)
+
+	composed := "\u00e9t\u00e9"
+	decomposed := "e\u0301te\u0301"
+
+	v := NewValidator()
+
+	assert.True(v.CheckStringNFC("a", composed))
+	assert.False(v.CheckStringNFC("a", decomposed))
+	assert.True(v.CheckStringNormalized("b", decomposed, norm.NFD))
+	assert.False(v.CheckStringNormalized("b", composed, norm.NFD))
+	assert.True(v.CheckStringNormalized("c", "abc", norm.NFKC))
+
+	if assert.Equal(2, len(v.Errors)) {
+		assert.Equal("string_not_normalized", v.Errors[0].Code)
+		assert.Equal("string must be in Unicode normalization form NFC",
+			v.Errors[0].Message)
+		assert.Equal("/b", v.Errors[1].Pointer.String())
+	}
+}
+
+func TestValidateStringURL(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := URLOptions{
+		Schemes:        []string{"https"},
+		RequireHost:    true,
+		ForbidUserinfo: true,
+		ForbidFragment: true,
+	}
+
+	tests := []struct {
+		s    string
+		code string
+	}{
+		{"https://example.com", ""},
+		{"HTTPS://example.com/a?b=c", ""},
+		{"%", "invalid_uri_format"},
+		{"/foo", "missing_uri_scheme"},
+		{"http://example.com", "forbidden_uri_scheme"},
+		{"https:/foo", "missing_uri_host"},
+		{"https://bob@example.com", "forbidden_uri_userinfo"},
+		{"https://example.com/#", "forbidden_uri_fragment"},
+	}
+
+	for _, test := range tests {
+		v := NewValidator()
+		valid := v.CheckStringURL("url", test.s, opts)
+
+		if test.code == "" {
+			assert.True(valid, test.s)
+			assert.Equal(0, len(v.Errors), test.s)
+		} else if assert.False(valid, test.s) &&
+			assert.Equal(1, len(v.Errors), test.s) {
+			assert.Equal(test.code, v.Errors[0].Code, test.s)
+		}
+	}
+
+	v := NewValidator()
+	assert.True(v.CheckStringURL("url", "mailto:bob@example.com#a",
+		URLOptions{}))
+}
+
+func TestField(t *testing.T) {
+	assert := assert.New(t)
+
+	type Base struct {
+		ID string `json:"id"`
+	}
+
+	type User struct {
+		Base
+		UserName string `json:"user_name,omitempty"`
+		Email    string
+		Password string `json:"-"`
+	}
+
+	var user *User
+
+	assert.Equal("user_name", Field(user, "UserName"))
+	assert.Equal("Email", Field(User{}, "Email"))
+	assert.Equal("id", Field(user, "ID"))
+	assert.Panics(func() { Field(user, "Password") })
+	assert.Panics(func() { Field(user, "Foo") })
+
+	v := NewValidator()
+	v.CheckStringNotEmpty(Field(user, "UserName"), "")
+	if assert.Equal(1, len(v.Errors)) {
+		assert.Equal("/user_name", v.Errors[0].Pointer.String())
+	}
+}
+
+func TestValidatorObjectTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	type plainBar struct {
+		Integers []int
+	}
+
+	bar := &TestBar{Integers: []int{42}}
+
+	for i := 0; i < 2; i++ {
+		v := NewValidator()
+
+		assert.True(v.CheckObjectArray("a", []*plainBar{{}}))
+		assert.True(v.CheckObjectMap("m", map[string]*plainBar{"x": {}}))
+		assert.False(v.CheckObjectArray("b", []interface{}{&plainBar{}, bar}))
+		assert.False(v.CheckObjectMap("n", map[string]interface{}{"y": bar}))
+
+		var pointers []string
+		for _, err := range v.Errors {
+			pointers = append(pointers, err.Pointer.String())
+		}
+
+		assert.Equal([]string{"/b/1/Integers/0", "/n/y/Integers/0"},
+			pointers)
+
+		assert.True(v.CheckObjectArray("c", []int{}))
+		assert.Panics(func() { v.CheckObjectArray("c", []int{1}) })
+		assert.Panics(func() { v.CheckObjectArray("c", bar) })
+		assert.Panics(func() { v.CheckObjectMap("c", map[int]*TestBar{}) })
+		assert.Panics(func() { v.CheckObject("c", TestBar{}) })
+	}
+}
+
+func TestValidatorReset(t *testing.T) {
+	assert := assert.New(t)
+
+	v := AcquireValidator()
+
+	v.WithChild("a", func() {
+		p := v.Pointer()
+
+		v.WithChild(NewPointer("b", "c"), func() {
+			v.CheckIntMin(0, 1, 2)
+			v.CheckIntMin(1, 1, 2)
+
+			assert.Equal("/a/b/c", v.Pointer().String())
+		})
+
+		assert.Equal("/a", p.String())
+
+		v.Push(nil)
+		v.CheckIntMax(nil, 2, 1)
+		v.Pop()
+	})
+
+	assert.Equal(0, len(v.Pointer()))
+
+	err := v.Error()
+
+	var validationErrs ValidationErrors
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+		}
+
+		assert.Equal([]string{"/a/b/c/0", "/a/b/c/1", "/a"}, pointers)
+	}
+
+	ReleaseValidator(v)
+
+	assert.Equal(0, len(v.Pointer()))
+	assert.Nil(v.Error())
+	assert.Equal(3, len(validationErrs))
+}
+
+func TestValidatorCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	// The value must be either a short string or a number between 0 and 10
+	checkValue := func(v *Validator, value interface{}) bool {
+		c := v.Checkpoint()
+
+		if s, ok := value.(string); ok &&
+			v.CheckStringLengthMax("value", s, 3) {
+			c.Commit()
+			return true
+		}
+
+		if f, ok := value.(float64); ok &&
+			v.CheckFloatMinMax("value", f, 0.0, 10.0) {
+			c.Rollback()
+			return true
+		}
+
+		c.Rollback()
+		v.AddError("value", "invalid_value",
+			"value must be a short string or a small number")
+		return false
+	}
+
+	v := NewValidator()
+	v.CheckIntMin("a", 1, 2)
+
+	assert.True(checkValue(v, "abc"))
+	assert.True(checkValue(v, 5.0))
+	assert.False(checkValue(v, "abcd"))
+	assert.False(checkValue(v, 42.0))
+
+	var codes []string
+	for _, err := range v.Errors {
+		codes = append(codes, err.Code)
+	}
+
+	assert.Equal([]string{"integer_too_small", "invalid_value",
+		"invalid_value"}, codes)
+
+	// Try
+	v = NewValidator()
+
+	errs := v.Try(func() {
+		v.Push("a")
+		v.CheckIntMin("b", 1, 2)
+		v.CheckIntMax("c", 3, 2)
+	})
+
+	assert.Equal(0, len(v.Errors))
+	assert.Equal(0, len(v.Pointer()))
+
+	if assert.Equal(2, len(errs)) {
+		assert.Equal("/a/b", errs[0].Pointer.String())
+		assert.Equal("/a/c", errs[1].Pointer.String())
+	}
+
+	assert.Nil(v.Try(func() { v.CheckIntMin("d", 3, 2) }))
+}
+
+func benchmarkFoo() *TestFoo {
+	foo := TestFoo{
+		String:   "abcdef",
+		Bar:      &TestBar{Integers: []int{1, 2, 3}},
+		BarTable: make(map[string]*TestBar),
+		Tag:      "a",
+	}
+
+	for i := 0; i < 100; i++ {
+		bar := TestBar{Integers: []int{1, 2, 3, 4, 5}}
+
+		foo.Bars = append(foo.Bars, &bar)
+		foo.BarTable[strconv.Itoa(i)] = &bar
+	}
+
+	return &foo
+}
+
+func BenchmarkValidate(b *testing.B) {
+	foo := benchmarkFoo()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := Validate(foo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateWithPool(b *testing.B) {
+	foo := benchmarkFoo()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		v := AcquireValidator()
+		v.CheckObject(nil, foo)
+
+		if err := v.Error(); err != nil {
+			b.Fatal(err)
+		}
+
+		ReleaseValidator(v)
+	}
+}
+
+func BenchmarkCheckObjectArray(b *testing.B) {
+	type plainBar struct {
+		Integers []int
+	}
+
+	bars := make([]*TestBar, 1000)
+	plainBars := make([]*plainBar, 1000)
+
+	for i := range bars {
+		bars[i] = &TestBar{Integers: []int{1, 2, 3}}
+		plainBars[i] = &plainBar{Integers: []int{1, 2, 3}}
+	}
+
+	v := NewValidator()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		v.CheckObjectArray("bars", bars)
+		v.CheckObjectArray("plain_bars", plainBars)
+	}
+
+	if len(v.Errors) > 0 {
+		b.Fatal(v.Error())
+	}
+}
+
+func BenchmarkValidateDeep(b *testing.B) {
+	foo := benchmarkFoo()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := ValidateDeep(foo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCheckStringValue(b *testing.B) {
+	type Tag string
+
+	values := []string{"a", "b", "c", "d"}
+	tagValues := []Tag{"a", "b", "c", "d"}
+
+	v := NewValidator()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		v.CheckStringValue("tag", "c", values)
+		v.CheckStringValue("tag", Tag("c"), tagValues)
+	}
+
+	if len(v.Errors) > 0 {
+		b.Fatal(v.Error())
+	}
+}
@@ -0,0 +1,481 @@
+package ejson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// DecodeValue decodes a generic JSON value into the value pointed to by dest
+// the same way Unmarshal decodes its serialized representation, without
+// encoding it first. Types implementing json.Unmarshaler are decoded from the
+// serialized representation of their value.
+//
+// All values which cannot be decoded are reported as ValidationErrors with
+// the pointer of the value. The destination is then validated with Validate.
+func DecodeValue(src interface{}, dest interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dest)}
+	}
+
+	err := decodeValueTo(src, dest)
+	recordValidation(err)
+	return err
+}
+
+func decodeValueTo(src interface{}, dest interface{}) error {
+	if err := runDecodingCheck(dest, src); err != nil {
+		return err
+	}
+
+	var errs ValidationErrors
+	decodeValue(reflect.ValueOf(dest).Elem(), src, Pointer{}, &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return validate(dest)
+}
+
+func decodeValue(dest reflect.Value, value interface{}, p Pointer, errs *ValidationErrors) {
+	t := dest.Type()
+
+	value = jsonScalarValue(value)
+
+	addError := func(code, format string, args ...interface{}) {
+		*errs = append(*errs, &ValidationError{
+			Pointer: p,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	typeError := func() {
+		addError("invalid_value_type", "cannot decode %s into value of "+
+			"type %v", jsonValueDescription(value), t)
+	}
+
+	if t.Kind() != reflect.Pointer && dest.CanAddr() {
+		switch v := dest.Addr().Interface().(type) {
+		case json.Unmarshaler:
+			data, err := json.Marshal(value)
+			if err != nil {
+				addError("invalid_value", "%v", err)
+				return
+			}
+
+			if err := v.UnmarshalJSON(data); err != nil {
+				if _, ok := err.(*json.UnmarshalTypeError); ok {
+					typeError()
+				} else {
+					addError("invalid_value", "%v", err)
+				}
+			}
+
+			return
+
+		case encoding.TextUnmarshaler:
+			if s, ok := value.(string); ok {
+				if err := v.UnmarshalText([]byte(s)); err != nil {
+					addError("invalid_value", "%v", err)
+				}
+
+				return
+			}
+		}
+	}
+
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			dest.Set(reflect.Zero(t))
+		}
+
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if dest.IsNil() {
+			dest.Set(reflect.New(t.Elem()))
+		}
+
+		decodeValue(dest.Elem(), value, p, errs)
+
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			typeError()
+			return
+		}
+
+		value2, ok := cloneGoValue(value)
+		if !ok {
+			typeError()
+			return
+		}
+
+		dest.Set(reflect.ValueOf(value2))
+
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			typeError()
+			return
+		}
+
+		dest.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i, ok := AsIntegerOK(value)
+		if !ok || dest.OverflowInt(i) {
+			typeError()
+			return
+		}
+
+		dest.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		i, ok := asUnsignedIntegerOK(value)
+		if !ok || dest.OverflowUint(i) {
+			typeError()
+			return
+		}
+
+		dest.SetUint(i)
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := AsNumberOK(value)
+		if !ok || dest.OverflowFloat(f) {
+			typeError()
+			return
+		}
+
+		dest.SetFloat(f)
+
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			typeError()
+			return
+		}
+
+		dest.SetString(s)
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// As encoding/json, decode byte slices from base64 strings
+			s, ok := value.(string)
+			if !ok {
+				typeError()
+				return
+			}
+
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				addError("invalid_base64_data", "invalid base64 data")
+				return
+			}
+
+			dest.SetBytes(data)
+			return
+		}
+
+		array, ok := value.([]interface{})
+		if !ok {
+			typeError()
+			return
+		}
+
+		slice := reflect.MakeSlice(t, len(array), len(array))
+		for i, element := range array {
+			decodeValue(slice.Index(i), element, p.Child(i), errs)
+		}
+
+		dest.Set(slice)
+
+	case reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			typeError()
+			return
+		}
+
+		for i := 0; i < dest.Len(); i++ {
+			if i < len(array) {
+				decodeValue(dest.Index(i), array[i], p.Child(i), errs)
+			} else {
+				dest.Index(i).Set(reflect.Zero(t.Elem()))
+			}
+		}
+
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			typeError()
+			return
+		}
+
+		if dest.IsNil() {
+			dest.Set(reflect.MakeMapWithSize(t, len(obj)))
+		}
+
+		for _, name := range sortedMemberNames(obj) {
+			key, err := decodeMapKey(t.Key(), name)
+			if err != nil {
+				*errs = append(*errs, &ValidationError{
+					Pointer: p.Child(name),
+					Code:    "invalid_value_type",
+					Message: fmt.Sprintf("cannot decode object member name "+
+						"into value of type %v", t.Key()),
+				})
+
+				continue
+			}
+
+			elem := reflect.New(t.Elem()).Elem()
+			decodeValue(elem, obj[name], p.Child(name), errs)
+
+			dest.SetMapIndex(key, elem)
+		}
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			typeError()
+			return
+		}
+
+		decodeStructValue(dest, obj, p, errs)
+
+	default:
+		typeError()
+	}
+}
+
+// Generic values built in Go can contain numbers of any numeric type; they
+// are converted to JSON numbers. Integers are converted to json.Number values
+// so that no precision is lost.
+func jsonScalarValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, bool, float64, json.Number, string,
+		[]interface{}, map[string]interface{}:
+		return value
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(v.Uint(), 10))
+
+	case reflect.Float32:
+		// Use the shortest representation of the float32 value, as
+		// encoding/json does.
+		f, _ := strconv.ParseFloat(
+			strconv.FormatFloat(v.Float(), 'g', -1, 32), 64)
+		return f
+
+	case reflect.Float64:
+		return v.Float()
+	}
+
+	return value
+}
+
+// Clone a generic value for an interface destination as Clone does, but
+// converting Go numbers and reporting values which cannot be represented in
+// JSON instead of panicking.
+func cloneGoValue(value interface{}) (interface{}, bool) {
+	switch v := jsonScalarValue(value).(type) {
+	case nil, bool, float64, json.Number, string:
+		return v, true
+
+	case []interface{}:
+		if v == nil {
+			return v, true
+		}
+
+		array := make([]interface{}, len(v))
+		for i, element := range v {
+			element2, ok := cloneGoValue(element)
+			if !ok {
+				return nil, false
+			}
+
+			array[i] = element2
+		}
+
+		return array, true
+
+	case map[string]interface{}:
+		if v == nil {
+			return v, true
+		}
+
+		obj := make(map[string]interface{}, len(v))
+		for key, memberValue := range v {
+			memberValue2, ok := cloneGoValue(memberValue)
+			if !ok {
+				return nil, false
+			}
+
+			obj[key] = memberValue2
+		}
+
+		return obj, true
+	}
+
+	return nil, false
+}
+
+func asUnsignedIntegerOK(value interface{}) (uint64, bool) {
+	if n, ok := value.(json.Number); ok {
+		if i, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return i, true
+		}
+	}
+
+	f, ok := AsNumberOK(value)
+	if !ok || f < 0 || f != math.Trunc(f) || f >= 1<<64 {
+		return 0, false
+	}
+
+	return uint64(f), true
+}
+
+func decodeStructValue(dest reflect.Value, obj map[string]interface{}, p Pointer, errs *ValidationErrors) {
+	info := getStructInfo(dest.Type())
+
+	var anyMembers map[string]interface{}
+
+	for _, name := range sortedMemberNames(obj) {
+		memberValue := obj[name]
+
+		field := info.Field(name)
+		if field == nil {
+			if info.AnyFieldIndex != nil {
+				if anyMembers == nil {
+					anyMembers = make(map[string]interface{})
+				}
+
+				memberValue2, ok := cloneGoValue(memberValue)
+				if !ok {
+					*errs = append(*errs, &ValidationError{
+						Pointer: p.Child(name),
+						Code:    "invalid_value_type",
+						Message: fmt.Sprintf("cannot decode %s into a json "+
+							"value", jsonValueDescription(memberValue)),
+					})
+
+					continue
+				}
+
+				anyMembers[name] = memberValue2
+			}
+
+			continue
+		}
+
+		fieldValue := settableFieldByIndex(dest, field.Index)
+		if !fieldValue.IsValid() {
+			continue
+		}
+
+		if field.Quoted && memberValue != nil {
+			s, ok := memberValue.(string)
+			if !ok {
+				*errs = append(*errs, &ValidationError{
+					Pointer: p.Child(name),
+					Code:    "invalid_value_type",
+					Message: fmt.Sprintf("cannot decode %s into quoted "+
+						"value of type %v", jsonValueDescription(memberValue),
+						field.Type),
+				})
+
+				continue
+			}
+
+			if err := json.Unmarshal([]byte(s), &memberValue); err != nil {
+				*errs = append(*errs, &ValidationError{
+					Pointer: p.Child(name),
+					Code:    "invalid_value_type",
+					Message: fmt.Sprintf("invalid quoted value %q", s),
+				})
+
+				continue
+			}
+		}
+
+		decodeValue(fieldValue, memberValue, p.Child(name), errs)
+
+		if field.TimeFormats != nil {
+			decodeTimePass(fieldValue, memberValue, field.TimeFormats)
+		}
+	}
+
+	if anyMembers != nil {
+		anyField := settableFieldByIndex(dest, info.AnyFieldIndex)
+		if anyField.IsValid() {
+			anyField.Set(reflect.ValueOf(anyMembers))
+		}
+	}
+}
+
+func decodeMapKey(t reflect.Type, name string) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		key := reflect.New(t)
+
+		err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText(
+			[]byte(name))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return key.Elem(), nil
+	}
+
+	key := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(name)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || key.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("invalid integer")
+		}
+
+		key.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(name, 10, 64)
+		if err != nil || key.OverflowUint(i) {
+			return reflect.Value{}, fmt.Errorf("invalid integer")
+		}
+
+		key.SetUint(i)
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported map key type %v", t)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,198 @@
+package ejson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.n16f.net/uuid"
+)
+
+type TestDecodedValue struct {
+	Id       uuid.UUID          `json:"id"`
+	Count    int                `json:"count"`
+	Ratio    float32            `json:"ratio,omitempty"`
+	Quoted   int64              `json:"quoted,string"`
+	Labels   map[string]string  `json:"labels"`
+	Indexes  map[int]bool       `json:"indexes"`
+	Data     []byte             `json:"data"`
+	Points   [2]int             `json:"points"`
+	Payload  interface{}        `json:"payload"`
+	Name     Nullable[string]   `json:"name"`
+	Event    *TestEvent         `json:"event"`
+	Children []*TestExtensible  `json:"children"`
+	Extra    map[string]any     `json:"-" ejson:"any"`
+	Nested   *TestDecodedNested `json:"nested"`
+}
+
+func (d *TestDecodedValue) ValidateJSON(v *Validator) {
+	v.CheckObjectArray("children", d.Children)
+}
+
+type TestDecodedNested struct {
+	Value uint8 `json:"value"`
+}
+
+func TestDecodeValue(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `{
+  "id": "4f7b3c4e-8c3a-4a86-9c3b-2a51a9a3a3d1",
+  "count": 3,
+  "ratio": 0.5,
+  "quoted": "42",
+  "labels": {"a": "x"},
+  "indexes": {"1": true, "2": false},
+  "data": "aGVsbG8=",
+  "points": [1],
+  "payload": {"a": [1, null]},
+  "name": null,
+  "event": {"date": 1700000000, "timestamp": 1700000000500},
+  "children": [{"name": "a", "x-foo": true}],
+  "x-extra": 1,
+  "nested": {"value": 255}
+}`
+
+	var value interface{}
+	if err := Unmarshal([]byte(data), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, decoded TestDecodedValue
+	if !assert.NoError(Unmarshal([]byte(data), &expected)) {
+		return
+	}
+
+	if assert.NoError(DecodeValue(value, &decoded)) {
+		assert.Equal(expected, decoded)
+
+		assert.Equal(3, decoded.Count)
+		assert.Equal(int64(42), decoded.Quoted)
+		assert.Equal([]byte("hello"), decoded.Data)
+		assert.True(decoded.Name.IsNull())
+		assert.Equal(int64(1700000000500), decoded.Event.Timestamp.UnixMilli())
+		assert.Equal(TimeFormatUnixMilli, decoded.Event.Timestamp.JSONFormat())
+		assert.Equal(map[string]interface{}{"x-foo": true},
+			decoded.Children[0].Extensions)
+		assert.Equal(map[string]interface{}{"x-extra": 1.0}, decoded.Extra)
+	}
+
+	// The source value must not be shared with the destination
+	decoded.Payload.(map[string]interface{})["b"] = true
+	assert.NotContains(value.(map[string]interface{})["payload"], "b")
+}
+
+func TestDecodeValueErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	assertErrors := func(expectedPointers []string, expectedCodes []string, data string) {
+		t.Helper()
+
+		var value interface{}
+		if err := Unmarshal([]byte(data), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded TestDecodedValue
+		err := DecodeValue(value, &decoded)
+
+		var validationErrs ValidationErrors
+		if assert.ErrorAs(err, &validationErrs, data) {
+			var pointers, codes []string
+			for _, err := range validationErrs {
+				pointers = append(pointers, err.Pointer.String())
+				codes = append(codes, err.Code)
+			}
+
+			assert.Equal(expectedPointers, pointers, data)
+			assert.Equal(expectedCodes, codes, data)
+		}
+	}
+
+	assertErrors([]string{"/count", "/labels/b", "/nested/value",
+		"/points/1"},
+		[]string{"invalid_value_type", "invalid_value_type",
+			"invalid_value_type", "invalid_value_type"},
+		`{"count": 1.5, "labels": {"a": "x", "b": 2}, "nested": {"value": 256},
+"points": [1, "2"]}`)
+
+	assertErrors([]string{"/event/date", "/event/timestamp"},
+		[]string{"invalid_time", "invalid_time"},
+		`{"event": {"date": "foo", "timestamp": "bar"}, "count": "x"}`)
+
+	assertErrors([]string{"/id", "/indexes/x", "/quoted"},
+		[]string{"invalid_value", "invalid_value_type", "invalid_value_type"},
+		`{"id": "foo", "indexes": {"x": true}, "quoted": 42}`)
+
+	assertErrors([]string{"/children/0/foo"}, []string{"unknown_member"},
+		`{"children": [{"name": "a", "foo": 1}]}`)
+
+	var n int
+	assert.Error(DecodeValue(1.0, n))
+	assert.Error(DecodeValue(1.0, nil))
+}
+
+func TestDecodeValueNumbers(t *testing.T) {
+	assert := assert.New(t)
+
+	var i int64
+	if assert.NoError(DecodeValue(json.Number("9007199254740993"), &i)) {
+		assert.Equal(int64(9007199254740993), i)
+	}
+
+	var u uint64
+	if assert.NoError(DecodeValue(json.Number("18446744073709551615"), &u)) {
+		assert.Equal(uint64(math.MaxUint64), u)
+	}
+
+	var f float32
+	if assert.NoError(DecodeValue(json.Number("0.5"), &f)) {
+		assert.Equal(float32(0.5), f)
+	}
+
+	var i8 int8
+	assert.Error(DecodeValue(json.Number("128"), &i8))
+	assert.Error(DecodeValue(json.Number("1.5"), &i))
+	assert.Error(DecodeValue(-1.0, &u))
+	assert.Error(DecodeValue(1e20, &u))
+
+	// Values built in Go
+	var nested TestDecodedNested
+	err := DecodeValue(map[string]interface{}{"value": 5}, &nested)
+	if assert.NoError(err) {
+		assert.Equal(uint8(5), nested.Value)
+	}
+
+	assert.Error(DecodeValue(map[string]interface{}{"value": 256}, &nested))
+
+	if assert.NoError(DecodeValue(int64(math.MaxInt64), &i)) {
+		assert.Equal(int64(math.MaxInt64), i)
+	}
+
+	if assert.NoError(DecodeValue(float32(0.1), &f)) {
+		assert.Equal(float32(0.1), f)
+	}
+
+	var v interface{}
+	err = DecodeValue([]interface{}{1, uint8(2), 0.5, "x"}, &v)
+	if assert.NoError(err) {
+		assert.Equal([]interface{}{json.Number("1"), json.Number("2"), 0.5,
+			"x"}, v)
+	}
+
+	var validationErrs ValidationErrors
+	err = DecodeValue(map[string]interface{}{"payload": struct{}{}},
+		&TestDecodedValue{})
+	if assert.ErrorAs(err, &validationErrs) {
+		assert.Equal("/payload", validationErrs[0].Pointer.String())
+		assert.Equal("invalid_value_type", validationErrs[0].Code)
+	}
+
+	err = DecodeValue(map[string]interface{}{"x-foo": []interface{}{
+		make(chan int)}}, &TestDecodedValue{})
+	if assert.ErrorAs(err, &validationErrs) {
+		assert.Equal("/x-foo", validationErrs[0].Pointer.String())
+		assert.Equal("invalid_value_type", validationErrs[0].Code)
+	}
+}
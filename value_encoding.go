@@ -0,0 +1,312 @@
+package ejson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeValue returns the generic JSON value which would be obtained by
+// decoding the result of json.Marshal applied to the value, without encoding
+// data. Types implementing json.Marshaler are encoded with their MarshalJSON
+// method and the result is decoded.
+func EncodeValue(src interface{}) (interface{}, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	return encodeValue(reflect.ValueOf(src), make(encodingRefs))
+}
+
+// Pointers, maps and slices being encoded, so that cycles are detected
+// instead of causing a stack overflow. As for encoding/json, slices are
+// identified by both their data pointer and their length.
+type encodingRef struct {
+	ptr uintptr
+	t   reflect.Type
+	len int
+}
+
+type encodingRefs map[encodingRef]struct{}
+
+func (refs encodingRefs) enter(v reflect.Value, ref encodingRef) error {
+	if _, found := refs[ref]; found {
+		return &json.UnsupportedValueError{
+			Value: v,
+			Str:   fmt.Sprintf("encountered a cycle via %v", v.Type()),
+		}
+	}
+
+	refs[ref] = struct{}{}
+	return nil
+}
+
+func encodeValue(v reflect.Value, refs encodingRefs) (interface{}, error) {
+	t := v.Type()
+
+	if t.Implements(jsonMarshalerType) {
+		if t.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, nil
+		}
+
+		return encodeMarshaler(v.Interface().(json.Marshaler))
+	} else if t.Kind() != reflect.Pointer && v.CanAddr() &&
+		reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return encodeMarshaler(v.Addr().Interface().(json.Marshaler))
+	}
+
+	if t.Implements(textMarshalerType) {
+		if t.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, nil
+		}
+
+		return encodeTextMarshaler(v.Interface().(encoding.TextMarshaler))
+	} else if t.Kind() != reflect.Pointer && v.CanAddr() &&
+		reflect.PointerTo(t).Implements(textMarshalerType) {
+		return encodeTextMarshaler(v.Addr().Interface().(encoding.TextMarshaler))
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		ref := encodingRef{ptr: v.Pointer(), t: t}
+		if err := refs.enter(v, ref); err != nil {
+			return nil, err
+		}
+		defer delete(refs, ref)
+
+		return encodeValue(v.Elem(), refs)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return encodeValue(v.Elem(), refs)
+
+	case reflect.Bool:
+		return v.Bool(), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return float64(v.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, &json.UnsupportedValueError{
+				Value: v,
+				Str:   strconv.FormatFloat(f, 'g', -1, t.Bits()),
+			}
+		}
+
+		if t.Kind() == reflect.Float32 {
+			// Use the shortest representation of the float32 value, as
+			// encoding/json does.
+			f, _ = strconv.ParseFloat(
+				strconv.FormatFloat(f, 'g', -1, 32), 64)
+		}
+
+		return f, nil
+
+	case reflect.String:
+		return v.String(), nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if t.Elem().Kind() == reflect.Uint8 &&
+			!reflect.PointerTo(t.Elem()).Implements(jsonMarshalerType) &&
+			!reflect.PointerTo(t.Elem()).Implements(textMarshalerType) {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+
+		ref := encodingRef{ptr: v.Pointer(), t: t, len: v.Len()}
+		if err := refs.enter(v, ref); err != nil {
+			return nil, err
+		}
+		defer delete(refs, ref)
+
+		return encodeArray(v, refs)
+
+	case reflect.Array:
+		return encodeArray(v, refs)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		ref := encodingRef{ptr: v.Pointer(), t: t}
+		if err := refs.enter(v, ref); err != nil {
+			return nil, err
+		}
+		defer delete(refs, ref)
+
+		obj := make(map[string]interface{}, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := encodeMapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := encodeValue(iter.Value(), refs)
+			if err != nil {
+				return nil, err
+			}
+
+			obj[key] = value
+		}
+
+		return obj, nil
+
+	case reflect.Struct:
+		return encodeStruct(v, refs)
+	}
+
+	return nil, &json.UnsupportedTypeError{Type: t}
+}
+
+func encodeMarshaler(m json.Marshaler) (interface{}, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, &json.MarshalerError{Type: reflect.TypeOf(m), Err: err}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, &json.MarshalerError{Type: reflect.TypeOf(m), Err: err}
+	}
+
+	return value, nil
+}
+
+func encodeTextMarshaler(m encoding.TextMarshaler) (interface{}, error) {
+	data, err := m.MarshalText()
+	if err != nil {
+		return nil, &json.MarshalerError{Type: reflect.TypeOf(m), Err: err}
+	}
+
+	return string(data), nil
+}
+
+func encodeArray(v reflect.Value, refs encodingRefs) (interface{}, error) {
+	array := make([]interface{}, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		element, err := encodeValue(v.Index(i), refs)
+		if err != nil {
+			return nil, err
+		}
+
+		array[i] = element
+	}
+
+	return array, nil
+}
+
+func encodeMapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if m, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Pointer && key.IsNil() {
+			return "", nil
+		}
+
+		data, err := m.MarshalText()
+		if err != nil {
+			return "", &json.MarshalerError{Type: key.Type(), Err: err}
+		}
+
+		return string(data), nil
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+
+	return "", &json.UnsupportedTypeError{Type: key.Type()}
+}
+
+func encodeStruct(v reflect.Value, refs encodingRefs) (interface{}, error) {
+	info := getStructInfo(v.Type())
+
+	obj := make(map[string]interface{}, len(info.Fields))
+
+	for _, field := range info.Fields {
+		fieldValue := fieldByIndex(v, field.Index)
+		if !fieldValue.IsValid() {
+			continue
+		}
+
+		if field.OmitEmpty && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		value, err := encodeValue(fieldValue, refs)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Quoted && value != nil {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+
+			value = string(data)
+		}
+
+		obj[field.Name] = value
+	}
+
+	return obj, nil
+}
+
+// The definition of empty values used for the "omitempty" option of json
+// tags.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
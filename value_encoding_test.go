@@ -0,0 +1,112 @@
+package ejson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.n16f.net/uuid"
+)
+
+type TestEncodedValue struct {
+	Id        uuid.UUID         `json:"id"`
+	Count     int               `json:"count,omitempty"`
+	Ratio     float32           `json:"ratio"`
+	Quoted    int64             `json:"quoted,string"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Indexes   map[int]bool      `json:"indexes"`
+	Data      []byte            `json:"data"`
+	Points    [2]int            `json:"points"`
+	Payload   interface{}       `json:"payload"`
+	Name      Nullable[string]  `json:"name"`
+	Timestamp Time              `json:"timestamp"`
+	Duration  Duration          `json:"duration"`
+	Next      *TestEncodedValue `json:"next,omitempty"`
+	Ignored   string            `json:"-"`
+	TestEncodedEmbedded
+}
+
+type TestEncodedEmbedded struct {
+	Extra string
+}
+
+func TestEncodeValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assertEncodeValue := func(src interface{}) {
+		t.Helper()
+
+		data, err := json.Marshal(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected interface{}
+		if err := json.Unmarshal(data, &expected); err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := EncodeValue(src)
+		if assert.NoError(err) {
+			assert.Equal(expected, value)
+		}
+	}
+
+	value := TestEncodedValue{
+		Id:        uuid.MustParse("4f7b3c4e-8c3a-4a86-9c3b-2a51a9a3a3d1"),
+		Ratio:     0.1,
+		Quoted:    42,
+		Indexes:   map[int]bool{1: true, -2: false},
+		Data:      []byte("hello"),
+		Points:    [2]int{1, 2},
+		Payload:   map[string]interface{}{"a": []interface{}{1.0, nil}},
+		Name:      NewNullable("x"),
+		Timestamp: NewTime(time.Unix(1700000000, 0), TimeFormatUnixMilli),
+		Duration:  Duration(90 * time.Second),
+		Next:      &TestEncodedValue{Count: 3, Name: NewNull[string]()},
+		Ignored:   "x",
+		TestEncodedEmbedded: TestEncodedEmbedded{
+			Extra: "y",
+		},
+	}
+
+	assertEncodeValue(value)
+	assertEncodeValue(&value)
+	assertEncodeValue([]interface{}{&value, nil, "x", 1, true})
+	assertEncodeValue(map[string]*TestEncodedValue{"a": nil, "b": &value})
+
+	v, err := EncodeValue(nil)
+	if assert.NoError(err) {
+		assert.Nil(v)
+	}
+
+	_, err = EncodeValue(math.NaN())
+	assert.Error(err)
+
+	_, err = EncodeValue(make(chan int))
+	assert.Error(err)
+
+	// Shared values are not cycles
+	shared := &TestEncodedValue{Count: 4}
+	assertEncodeValue([]*TestEncodedValue{shared, shared})
+
+	// Cycles
+	var unsupportedValueErr *json.UnsupportedValueError
+
+	cyclic := &TestEncodedValue{Count: 5}
+	cyclic.Next = cyclic
+	_, err = EncodeValue(cyclic)
+	assert.ErrorAs(err, &unsupportedValueErr)
+
+	cyclicMap := map[string]interface{}{}
+	cyclicMap["a"] = cyclicMap
+	_, err = EncodeValue(cyclicMap)
+	assert.ErrorAs(err, &unsupportedValueErr)
+
+	cyclicSlice := []interface{}{nil}
+	cyclicSlice[0] = cyclicSlice
+	_, err = EncodeValue(cyclicSlice)
+	assert.ErrorAs(err, &unsupportedValueErr)
+}
@@ -0,0 +1,138 @@
+package ejson
+
+// ValueValidator validates generic JSON values. Its check functions resolve
+// values using a JSON pointer relative to the current pointer of the
+// validator, report an error if the value is missing or has an invalid type,
+// and otherwise call a function with the value. During this call, the
+// current pointer of the validator is the pointer of the value, so that
+// checks can be performed with a nil token, e.g.:
+//
+//	vv.CheckString("/user/email", func(s string) {
+//		vv.CheckEmailAddress(nil, s)
+//	})
+type ValueValidator struct {
+	*Validator
+
+	Value interface{}
+}
+
+func NewValueValidator(value interface{}) *ValueValidator {
+	return &ValueValidator{
+		Validator: NewValidator(),
+		Value:     value,
+	}
+}
+
+// ValidateValue calls fn with a validator for a generic JSON value and
+// returns validation errors if there are any.
+func ValidateValue(value interface{}, fn func(*ValueValidator)) error {
+	vv := NewValueValidator(value)
+	fn(vv)
+	return vv.Error()
+}
+
+// Return the pointer relative to the current pointer of the validator, and
+// the value it references if there is one.
+func (vv *ValueValidator) resolve(s string) (Pointer, interface{}, bool) {
+	var p Pointer
+	p.MustParse(s)
+
+	value, err := vv.Pointer().Child(p).Resolve(vv.Value)
+	if err != nil {
+		return p, nil, false
+	}
+
+	return p, value, true
+}
+
+// Exists returns true if there is a value, possibly null, at the pointer.
+func (vv *ValueValidator) Exists(pointer string) bool {
+	_, _, found := vv.resolve(pointer)
+	return found
+}
+
+func (vv *ValueValidator) CheckPresent(pointer string) bool {
+	p, _, found := vv.resolve(pointer)
+	return vv.Check(p, found, "missing_value", "missing value")
+}
+
+func (vv *ValueValidator) CheckNotNull(pointer string) bool {
+	p, value, found := vv.resolve(pointer)
+	if !found {
+		vv.AddError(p, "missing_value", "missing value")
+		return false
+	}
+
+	return vv.Check(p, value != nil, "null_value", "value must not be null")
+}
+
+func (vv *ValueValidator) checkValue(pointer string, typeName string, typeFn func(interface{}) bool, fn func(interface{})) bool {
+	p, value, found := vv.resolve(pointer)
+
+	switch {
+	case !found:
+		vv.AddError(p, "missing_value", "missing value")
+		return false
+
+	case value == nil:
+		vv.AddError(p, "null_value", "value must not be null")
+		return false
+
+	case !typeFn(value):
+		vv.AddError(p, "invalid_value_type", "value must be %s", typeName)
+		return false
+	}
+
+	nbErrors := len(vv.Errors)
+
+	vv.WithChild(p, func() { fn(value) })
+
+	return len(vv.Errors) == nbErrors
+}
+
+func (vv *ValueValidator) CheckString(pointer string, fn func(string)) bool {
+	return vv.checkValue(pointer, "a string", IsString,
+		func(value interface{}) {
+			if fn != nil {
+				fn(AsString(value))
+			}
+		})
+}
+
+func (vv *ValueValidator) CheckNumber(pointer string, fn func(float64)) bool {
+	return vv.checkValue(pointer, "a number", IsNumber,
+		func(value interface{}) {
+			if fn != nil {
+				fn(AsNumber(value))
+			}
+		})
+}
+
+func (vv *ValueValidator) CheckBoolean(pointer string, fn func(bool)) bool {
+	return vv.checkValue(pointer, "a boolean", IsBoolean,
+		func(value interface{}) {
+			if fn != nil {
+				fn(AsBoolean(value))
+			}
+		})
+}
+
+func (vv *ValueValidator) CheckArray(pointer string, fn func([]interface{})) bool {
+	return vv.checkValue(pointer, "an array", IsArray,
+		func(value interface{}) {
+			if fn != nil {
+				fn(AsArray(value))
+			}
+		})
+}
+
+// CheckGenericObject is named this way to avoid conflicting with
+// Validator.CheckObject which validates structures.
+func (vv *ValueValidator) CheckGenericObject(pointer string, fn func(map[string]interface{})) bool {
+	return vv.checkValue(pointer, "an object", IsObject,
+		func(value interface{}) {
+			if fn != nil {
+				fn(AsObject(value))
+			}
+		})
+}
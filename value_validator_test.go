@@ -0,0 +1,79 @@
+package ejson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValue(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"user": {"name": "a", "email": "foo", "age": 12,
+"admin": "yes", "roles": ["x", 1]}, "null": null}`), &value)
+
+	err := ValidateValue(value, func(vv *ValueValidator) {
+		vv.CheckString("/user/name", func(s string) {
+			vv.CheckStringLengthMin(nil, s, 3)
+		})
+
+		vv.CheckString("/user/email", func(s string) {
+			vv.CheckEmailAddress(nil, s)
+		})
+
+		vv.CheckNumber("/user/age", func(n float64) {
+			vv.CheckFloatMin(nil, n, 18)
+		})
+
+		vv.CheckBoolean("/user/admin", nil)
+
+		vv.CheckArray("/user/roles", func(roles []interface{}) {
+			for i := range roles {
+				vv.CheckString(NewPointer(i).String(), nil)
+			}
+		})
+
+		vv.WithChild("user", func() {
+			vv.CheckGenericObject("/settings", nil)
+		})
+
+		vv.CheckNotNull("/null")
+		vv.CheckPresent("/null")
+		assert.True(vv.Exists("/null"))
+		assert.False(vv.Exists("/foo"))
+	})
+
+	var validationErrs ValidationErrors
+	if assert.ErrorAs(err, &validationErrs) {
+		var pointers, codes []string
+		for _, err := range validationErrs {
+			pointers = append(pointers, err.Pointer.String())
+			codes = append(codes, err.Code)
+		}
+
+		assert.Equal([]string{
+			"/user/name",
+			"/user/email",
+			"/user/age",
+			"/user/admin",
+			"/user/roles/1",
+			"/user/settings",
+			"/null",
+		}, pointers)
+
+		assert.Equal([]string{
+			"string_too_short",
+			"invalid_email_address",
+			"float_too_small",
+			"invalid_value_type",
+			"invalid_value_type",
+			"missing_value",
+			"null_value",
+		}, codes)
+	}
+
+	assert.NoError(ValidateValue(42.0, func(vv *ValueValidator) {
+		vv.CheckNumber("", nil)
+	}))
+}
@@ -1,6 +1,10 @@
 package ejson
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
 
 type InvalidValueError struct {
 	Value interface{}
@@ -15,8 +19,21 @@ func IsNull(v interface{}) bool {
 	return v == nil
 }
 
+// IsNumber returns true if the value is a number, either decoded as a
+// float64 or as a json.Number.
 func IsNumber(v interface{}) bool {
-	_, ok := v.(float64)
+	switch v.(type) {
+	case float64, json.Number:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsInteger returns true if the value is a number without any fractional
+// part which can be represented as an int64.
+func IsInteger(v interface{}) bool {
+	_, ok := AsIntegerOK(v)
 	return ok
 }
 
@@ -41,7 +58,21 @@ func IsObject(v interface{}) bool {
 }
 
 func AsNumber(v interface{}) float64 {
-	return v.(float64)
+	f, ok := AsNumberOK(v)
+	if !ok {
+		panic(fmt.Sprintf("value %#v (%T) is not a number", v, v))
+	}
+
+	return f
+}
+
+func AsInteger(v interface{}) int64 {
+	i, ok := AsIntegerOK(v)
+	if !ok {
+		panic(fmt.Sprintf("value %#v (%T) is not an integer", v, v))
+	}
+
+	return i
 }
 
 func AsString(v interface{}) string {
@@ -60,13 +91,116 @@ func AsObject(v interface{}) map[string]interface{} {
 	return v.(map[string]interface{})
 }
 
+func AsNumberOK(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0.0, false
+		}
+
+		return f, true
+
+	default:
+		return 0.0, false
+	}
+}
+
+func AsIntegerOK(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return floatToInt64(n)
+
+	case json.Number:
+		// Parse the number as an integer first to avoid losing precision
+		if i, err := n.Int64(); err == nil {
+			return i, true
+		}
+
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+
+		return floatToInt64(f)
+
+	default:
+		return 0, false
+	}
+}
+
+func floatToInt64(f float64) (int64, bool) {
+	// -2^63 is exactly representable as a float64, 2^63-1 is not
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= -math.MinInt64 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+func AsStringOK(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+func AsBooleanOK(v interface{}) (bool, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+func AsArrayOK(v interface{}) ([]interface{}, bool) {
+	a, ok := v.([]interface{})
+	return a, ok
+}
+
+func AsObjectOK(v interface{}) (map[string]interface{}, bool) {
+	obj, ok := v.(map[string]interface{})
+	return obj, ok
+}
+
+type EqualOptions struct {
+	// Numbers are considered equal if the absolute value of their difference
+	// is lower or equal to the tolerance.
+	NumberTolerance float64
+
+	// Arrays are compared as multisets, i.e. ignoring the order of their
+	// elements, if UnorderedArrays is true or if their pointer matches one of
+	// the patterns of UnorderedArrayPatterns.
+	UnorderedArrays        bool
+	UnorderedArrayPatterns []PointerPattern
+}
+
 func Equal(v1, v2 interface{}) bool {
+	return EqualWithOptions(v1, v2, EqualOptions{})
+}
+
+func EqualWithOptions(v1, v2 interface{}, opts EqualOptions) bool {
+	return equal(Pointer{}, v1, v2, &opts)
+}
+
+func equal(p Pointer, v1, v2 interface{}, opts *EqualOptions) bool {
+	// Pointers are only required to match patterns; do not allocate them
+	// otherwise.
+	child := func(token interface{}) Pointer {
+		if len(opts.UnorderedArrayPatterns) == 0 {
+			return nil
+		}
+
+		return p.Child(token)
+	}
+
 	switch {
 	case IsNull(v1) && IsNull(v2):
 		return true
 
 	case IsNumber(v1) && IsNumber(v2):
-		return AsNumber(v1) == AsNumber(v2)
+		n1 := AsNumber(v1)
+		n2 := AsNumber(v2)
+
+		return n1 == n2 || math.Abs(n1-n2) <= opts.NumberTolerance
 
 	case IsString(v1) && IsString(v2):
 		return AsString(v1) == AsString(v2)
@@ -82,8 +216,12 @@ func Equal(v1, v2 interface{}) bool {
 			return false
 		}
 
+		if opts.unorderedArray(p) {
+			return equalUnorderedArrays(p, a1, a2, opts)
+		}
+
 		for i := 0; i < len(a1); i++ {
-			if !Equal(a1[i], a2[i]) {
+			if !equal(child(i), a1[i], a2[i], opts) {
 				return false
 			}
 		}
@@ -96,14 +234,13 @@ func Equal(v1, v2 interface{}) bool {
 
 		for key, value1 := range obj1 {
 			value2, found := obj2[key]
-			if !found || !Equal(value1, value2) {
+			if !found || !equal(child(key), value1, value2, opts) {
 				return false
 			}
 		}
 
-		for key, value2 := range obj2 {
-			value1, found := obj1[key]
-			if !found || !Equal(value1, value2) {
+		for key := range obj2 {
+			if _, found := obj1[key]; !found {
 				return false
 			}
 		}
@@ -114,6 +251,50 @@ func Equal(v1, v2 interface{}) bool {
 	return false
 }
 
+func (opts *EqualOptions) unorderedArray(p Pointer) bool {
+	if opts.UnorderedArrays {
+		return true
+	}
+
+	for _, pattern := range opts.UnorderedArrayPatterns {
+		if pattern.Match(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Elements are matched greedily: with a number tolerance, arrays whose
+// elements could only be paired in a specific way may not be considered
+// equal.
+func equalUnorderedArrays(p Pointer, a1, a2 []interface{}, opts *EqualOptions) bool {
+	matched := make([]bool, len(a2))
+
+	for i, e1 := range a1 {
+		var ep Pointer
+		if len(opts.UnorderedArrayPatterns) > 0 {
+			ep = p.Child(i)
+		}
+
+		found := false
+
+		for j, e2 := range a2 {
+			if !matched[j] && equal(ep, e1, e2, opts) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
 func ObjectKeys(v interface{}) []string {
 	obj := AsObject(v)
 
@@ -141,3 +322,74 @@ func ObjectValues(v interface{}) []interface{} {
 
 	return values
 }
+
+func SortedObjectKeys(v interface{}) []string {
+	return sortedMemberNames(AsObject(v))
+}
+
+type ObjectEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// ObjectEntries returns the members of an object sorted by key.
+func ObjectEntries(v interface{}) []ObjectEntry {
+	obj := AsObject(v)
+
+	entries := make([]ObjectEntry, len(obj))
+	for i, key := range sortedMemberNames(obj) {
+		entries[i] = ObjectEntry{Key: key, Value: obj[key]}
+	}
+
+	return entries
+}
+
+// ForEachMember calls fn for each member of an object in lexicographic order
+// of their key. Iteration stops at the first error returned by fn, and the
+// error is returned.
+func ForEachMember(v interface{}, fn func(string, interface{}) error) error {
+	obj := AsObject(v)
+
+	for _, key := range sortedMemberNames(obj) {
+		if err := fn(key, obj[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of a generic JSON value. It panics if the value
+// contains data which are not valid JSON values.
+func Clone(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case nil, float64, json.Number, string, bool:
+		return tv
+
+	case []interface{}:
+		if tv == nil {
+			return tv
+		}
+
+		array := make([]interface{}, len(tv))
+		for i, element := range tv {
+			array[i] = Clone(element)
+		}
+
+		return array
+
+	case map[string]interface{}:
+		if tv == nil {
+			return tv
+		}
+
+		obj := make(map[string]interface{}, len(tv))
+		for key, value := range tv {
+			obj[key] = Clone(value)
+		}
+
+		return obj
+	}
+
+	panic(&InvalidValueError{Value: v})
+}
@@ -0,0 +1,165 @@
+package ejson
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	assert := assert.New(t)
+
+	value := map[string]interface{}{
+		"a": []interface{}{1.0, "x", true, nil, json.Number("42")},
+		"b": map[string]interface{}{"c": []interface{}{}},
+	}
+
+	value2 := Clone(value)
+	assert.Equal(value, value2)
+
+	obj2 := AsObject(value2)
+	AsArray(obj2["a"])[0] = 2.0
+	AsObject(obj2["b"])["d"] = 3.0
+
+	assert.Equal(1.0, AsArray(value["a"])[0])
+	assert.NotContains(AsObject(value["b"]), "d")
+
+	assert.Nil(Clone(nil))
+	assert.Equal("x", Clone("x"))
+
+	assert.Panics(func() { Clone([]interface{}{42}) })
+}
+
+func TestNumberAccessors(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsNull(nil))
+	assert.False(IsNull(0.0))
+
+	assert.True(IsNumber(1.5))
+	assert.True(IsNumber(json.Number("1.5")))
+	assert.False(IsNumber("1.5"))
+
+	assert.True(IsInteger(42.0))
+	assert.True(IsInteger(-1e18))
+	assert.True(IsInteger(json.Number("9007199254740993")))
+	assert.True(IsInteger(json.Number("1e3")))
+	assert.False(IsInteger(1.5))
+	assert.False(IsInteger(1e19))
+	assert.False(IsInteger(math.Inf(1)))
+	assert.False(IsInteger(math.NaN()))
+	assert.False(IsInteger(json.Number("1.5")))
+	assert.False(IsInteger(json.Number("foo")))
+	assert.False(IsInteger("1"))
+
+	i, ok := AsIntegerOK(json.Number("9007199254740993"))
+	if assert.True(ok) {
+		assert.Equal(int64(9007199254740993), i)
+	}
+
+	i, ok = AsIntegerOK(float64(math.MinInt64))
+	if assert.True(ok) {
+		assert.Equal(int64(math.MinInt64), i)
+	}
+
+	f, ok := AsNumberOK(json.Number("1.5"))
+	if assert.True(ok) {
+		assert.Equal(1.5, f)
+	}
+
+	_, ok = AsNumberOK(true)
+	assert.False(ok)
+
+	assert.Equal(int64(-3), AsInteger(-3.0))
+	assert.Equal(2.5, AsNumber(json.Number("2.5")))
+	assert.Panics(func() { AsInteger(2.5) })
+	assert.Panics(func() { AsNumber("2.5") })
+}
+
+func TestEqualWithOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	x, y := 0.1, 0.2
+
+	v1 := map[string]interface{}{"a": []interface{}{x + y, "x"}}
+	v2 := map[string]interface{}{"a": []interface{}{0.3, "x"}}
+
+	assert.False(Equal(v1, v2))
+	assert.True(EqualWithOptions(v1, v2, EqualOptions{NumberTolerance: 1e-9}))
+
+	opts := EqualOptions{NumberTolerance: 0.5}
+
+	assert.True(EqualWithOptions(1.0, 1.5, opts))
+	assert.False(EqualWithOptions(1.0, 1.6, opts))
+	assert.False(EqualWithOptions(1.0, "1", opts))
+	assert.False(EqualWithOptions(map[string]interface{}{"a": 1.0},
+		map[string]interface{}{"a": 1.0, "b": 2.0}, opts))
+	assert.True(EqualWithOptions(math.Inf(1), math.Inf(1), opts))
+}
+
+func TestEqualUnorderedArrays(t *testing.T) {
+	assert := assert.New(t)
+
+	var v1, v2 interface{}
+	Unmarshal([]byte(`{"tags": ["a", "b", "a"], "list": [1, 2],
+"items": [{"scopes": ["x", "y"]}]}`), &v1)
+	Unmarshal([]byte(`{"tags": ["b", "a", "a"], "list": [1, 2],
+"items": [{"scopes": ["y", "x"]}]}`), &v2)
+
+	assert.False(Equal(v1, v2))
+	assert.True(EqualWithOptions(v1, v2, EqualOptions{UnorderedArrays: true}))
+
+	var tagsPattern, scopesPattern PointerPattern
+	tagsPattern.MustParse("/tags")
+	scopesPattern.MustParse("/items/*/scopes")
+
+	assert.False(EqualWithOptions(v1, v2, EqualOptions{
+		UnorderedArrayPatterns: []PointerPattern{tagsPattern},
+	}))
+	assert.True(EqualWithOptions(v1, v2, EqualOptions{
+		UnorderedArrayPatterns: []PointerPattern{tagsPattern, scopesPattern},
+	}))
+
+	Unmarshal([]byte(`{"tags": ["b", "a", "a"], "list": [2, 1],
+"items": [{"scopes": ["y", "x"]}]}`), &v2)
+	assert.False(EqualWithOptions(v1, v2, EqualOptions{
+		UnorderedArrayPatterns: []PointerPattern{tagsPattern, scopesPattern},
+	}))
+
+	opts := EqualOptions{UnorderedArrays: true}
+	assert.False(EqualWithOptions([]interface{}{"a", "a", "b"},
+		[]interface{}{"a", "b", "b"}, opts))
+}
+
+func TestObjectIteration(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := map[string]interface{}{"c": 3.0, "a": 1.0, "b": 2.0, "": 0.0}
+
+	assert.Equal([]string{"", "a", "b", "c"}, SortedObjectKeys(obj))
+
+	assert.Equal([]ObjectEntry{
+		{"", 0.0}, {"a", 1.0}, {"b", 2.0}, {"c", 3.0},
+	}, ObjectEntries(obj))
+
+	stopErr := errors.New("stop")
+
+	var keys []string
+	err := ForEachMember(obj, func(key string, value interface{}) error {
+		keys = append(keys, key)
+
+		if AsNumber(value) == 2.0 {
+			return stopErr
+		}
+
+		return nil
+	})
+	assert.ErrorIs(err, stopErr)
+	assert.Equal([]string{"", "a", "b"}, keys)
+
+	assert.Empty(SortedObjectKeys(map[string]interface{}{}))
+	assert.Panics(func() { SortedObjectKeys([]interface{}{}) })
+}
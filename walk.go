@@ -0,0 +1,89 @@
+package ejson
+
+import "errors"
+
+// ErrSkipChildren can be returned by Walk and Transform callbacks to indicate
+// that the children of the current value must not be visited. It is never
+// returned by Walk or Transform themselves.
+var ErrSkipChildren = errors.New("skip children")
+
+// Walk performs a depth-first traversal of a generic JSON value, calling fn
+// for each value before visiting its children. Array elements are visited in
+// order and object members in lexicographic order of their name.
+//
+// If fn returns an error other than ErrSkipChildren, the traversal stops and
+// the error is returned.
+func Walk(value interface{}, fn func(Pointer, interface{}) error) error {
+	return walk(Pointer{}, value, fn)
+}
+
+func walk(p Pointer, value interface{}, fn func(Pointer, interface{}) error) error {
+	if err := fn(p, value); err != nil {
+		if err == ErrSkipChildren {
+			return nil
+		}
+
+		return err
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		for i, element := range v {
+			if err := walk(p.Child(i), element, fn); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range sortedMemberNames(v) {
+			if err := walk(p.Child(name), v[name], fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Transform traverses a generic JSON value in the same order as Walk and
+// replaces each value by the one returned by fn. The children of the returned
+// value are then visited. Arrays and objects are modified in place; the
+// function returns the new top-level value.
+func Transform(value interface{}, fn func(Pointer, interface{}) (interface{}, error)) (interface{}, error) {
+	return transform(Pointer{}, value, fn)
+}
+
+func transform(p Pointer, value interface{}, fn func(Pointer, interface{}) (interface{}, error)) (interface{}, error) {
+	value, err := fn(p, value)
+	if err != nil {
+		if err == ErrSkipChildren {
+			return value, nil
+		}
+
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		for i, element := range v {
+			element2, err := transform(p.Child(i), element, fn)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = element2
+		}
+
+	case map[string]interface{}:
+		for _, name := range sortedMemberNames(v) {
+			memberValue, err := transform(p.Child(name), v[name], fn)
+			if err != nil {
+				return nil, err
+			}
+
+			v[name] = memberValue
+		}
+	}
+
+	return value, nil
+}
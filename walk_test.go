@@ -0,0 +1,99 @@
+package ejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"b": [1, {"c": 2}], "a": null, "d": {"e": 3}}`),
+		&value)
+
+	var pointers []string
+
+	err := Walk(value, func(p Pointer, v interface{}) error {
+		pointers = append(pointers, p.String())
+
+		if p.String() == "/d" {
+			return ErrSkipChildren
+		}
+
+		return nil
+	})
+	if assert.NoError(err) {
+		assert.Equal([]string{"", "/a", "/b", "/b/0", "/b/1", "/b/1/c", "/d"},
+			pointers)
+	}
+
+	testErr := errors.New("test")
+
+	pointers = nil
+	err = Walk(value, func(p Pointer, v interface{}) error {
+		pointers = append(pointers, p.String())
+
+		if IsNumber(v) {
+			return testErr
+		}
+
+		return nil
+	})
+	assert.ErrorIs(err, testErr)
+	assert.Equal([]string{"", "/a", "/b", "/b/0"}, pointers)
+}
+
+func TestTransform(t *testing.T) {
+	assert := assert.New(t)
+
+	var value interface{}
+	Unmarshal([]byte(`{"name": "a", "password": "x",
+"children": [{"name": "b", "password": "y"}], "n": 1}`), &value)
+
+	value, err := Transform(value,
+		func(p Pointer, v interface{}) (interface{}, error) {
+			switch {
+			case len(p) > 0 && p[len(p)-1] == "password":
+				return "********", nil
+
+			case IsNumber(v):
+				return AsNumber(v) * 2, nil
+
+			case p.String() == "/children/0":
+				obj := AsObject(v)
+				obj["added"] = 1.0
+				return obj, nil
+			}
+
+			return v, nil
+		})
+	if assert.NoError(err) {
+		assert.Equal(map[string]interface{}{
+			"name":     "a",
+			"password": "********",
+			"children": []interface{}{
+				map[string]interface{}{
+					"name":     "b",
+					"password": "********",
+					"added":    2.0,
+				},
+			},
+			"n": 2.0,
+		}, value)
+	}
+
+	value, err = Transform(value,
+		func(p Pointer, v interface{}) (interface{}, error) {
+			if len(p) == 0 {
+				return []interface{}{1.0}, ErrSkipChildren
+			}
+
+			return nil, errors.New("unexpected call")
+		})
+	if assert.NoError(err) {
+		assert.Equal([]interface{}{1.0}, value)
+	}
+}